@@ -23,6 +23,7 @@ import (
 	"github.com/fatih/color"
 	"github.com/go-kit/log/level"
 	"github.com/gorilla/mux"
+	"github.com/grafana/agent/component"
 	"github.com/grafana/agent/pkg/cluster"
 	"github.com/grafana/agent/pkg/config/instrumentation"
 	"github.com/grafana/agent/pkg/flow"
@@ -98,19 +99,25 @@ depending on the nature of the reload error.
 		StringVar(&r.clusterJoinAddr, "cluster.join-addresses", r.clusterJoinAddr, "Comma-separated list of addresses to join the cluster at")
 	cmd.Flags().
 		BoolVar(&r.disableReporting, "disable-reporting", r.disableReporting, "Disable reporting of enabled components to Grafana.")
+	cmd.Flags().
+		StringSliceVar(&r.featureFlags, "feature.enable", r.featureFlags, "Comma-separated list of experimental component feature flags to enable")
+	cmd.Flags().
+		StringToStringVar(&r.componentLogLevels, "log.component-level", r.componentLogLevels, "Comma-separated list of component ID=level pairs overriding the log level of individual components")
 	return cmd
 }
 
 type flowRun struct {
-	inMemoryAddr     string
-	httpListenAddr   string
-	storagePath      string
-	uiPrefix         string
-	enablePprof      bool
-	disableReporting bool
-	clusterEnabled   bool
-	clusterAdvAddr   string
-	clusterJoinAddr  string
+	inMemoryAddr       string
+	httpListenAddr     string
+	storagePath        string
+	uiPrefix           string
+	enablePprof        bool
+	disableReporting   bool
+	clusterEnabled     bool
+	clusterAdvAddr     string
+	clusterJoinAddr    string
+	featureFlags       []string
+	componentLogLevels map[string]string
 }
 
 func (fr *flowRun) Run(configFile string) error {
@@ -124,6 +131,15 @@ func (fr *flowRun) Run(configFile string) error {
 		return fmt.Errorf("file argument not provided")
 	}
 
+	componentLogLevels := make(map[string]logging.Level, len(fr.componentLogLevels))
+	for id, text := range fr.componentLogLevels {
+		var lvl logging.Level
+		if err := lvl.UnmarshalText([]byte(text)); err != nil {
+			return fmt.Errorf("invalid log level for component %q: %w", id, err)
+		}
+		componentLogLevels[id] = lvl
+	}
+
 	logSink, err := logging.WriterSink(os.Stderr, logging.DefaultSinkOptions)
 	if err != nil {
 		return fmt.Errorf("building logger: %w", err)
@@ -172,14 +188,21 @@ func (fr *flowRun) Run(configFile string) error {
 	// In-memory listener, used for inner HTTP traffic without the network.
 	memLis := memconn.NewListener(nil)
 
+	featureFlags := make(component.FeatureFlags, len(fr.featureFlags))
+	for _, name := range fr.featureFlags {
+		featureFlags[name] = true
+	}
+
 	f := flow.New(flow.Options{
-		LogSink:        logSink,
-		Tracer:         t,
-		Clusterer:      clusterer,
-		DataPath:       fr.storagePath,
-		Reg:            reg,
-		HTTPPathPrefix: "/api/v0/component/",
-		HTTPListenAddr: fr.inMemoryAddr,
+		LogSink:            logSink,
+		ComponentLogLevels: componentLogLevels,
+		Tracer:             t,
+		Clusterer:          clusterer,
+		DataPath:           fr.storagePath,
+		Reg:                reg,
+		HTTPPathPrefix:     "/api/v0/component/",
+		HTTPListenAddr:     fr.inMemoryAddr,
+		FeatureFlags:       featureFlags,
 
 		// Send requests to fr.inMemoryAddr directly to our in-memory listener.
 		DialFunc: func(ctx context.Context, network, address string) (net.Conn, error) {
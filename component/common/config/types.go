@@ -17,15 +17,18 @@ const bearerAuth string = "Bearer"
 
 // HTTPClientConfig mirrors config.HTTPClientConfig
 type HTTPClientConfig struct {
-	BasicAuth       *BasicAuth        `river:"basic_auth,block,optional"`
-	Authorization   *Authorization    `river:"authorization,block,optional"`
-	OAuth2          *OAuth2Config     `river:"oauth2,block,optional"`
-	BearerToken     rivertypes.Secret `river:"bearer_token,attr,optional"`
-	BearerTokenFile string            `river:"bearer_token_file,attr,optional"`
-	ProxyURL        URL               `river:"proxy_url,attr,optional"`
-	TLSConfig       TLSConfig         `river:"tls_config,block,optional"`
-	FollowRedirects bool              `river:"follow_redirects,attr,optional"`
-	EnableHTTP2     bool              `river:"enable_http2,attr,optional"`
+	BasicAuth            *BasicAuth                     `river:"basic_auth,block,optional"`
+	Authorization        *Authorization                 `river:"authorization,block,optional"`
+	OAuth2               *OAuth2Config                  `river:"oauth2,block,optional"`
+	BearerToken          rivertypes.Secret              `river:"bearer_token,attr,optional"`
+	BearerTokenFile      string                         `river:"bearer_token_file,attr,optional"`
+	ProxyURL             URL                            `river:"proxy_url,attr,optional"`
+	NoProxy              string                         `river:"no_proxy,attr,optional"`
+	ProxyFromEnvironment bool                           `river:"proxy_from_environment,attr,optional"`
+	ProxyConnectHeader   map[string][]rivertypes.Secret `river:"proxy_connect_header,attr,optional"`
+	TLSConfig            TLSConfig                      `river:"tls_config,block,optional"`
+	FollowRedirects      bool                           `river:"follow_redirects,attr,optional"`
+	EnableHTTP2          bool                           `river:"enable_http2,attr,optional"`
 }
 
 // UnmarshalRiver implements the umarshaller
@@ -54,6 +57,18 @@ func (h *HTTPClientConfig) Validate() error {
 	if h.BasicAuth != nil && (string(h.BasicAuth.Password) != "" && h.BasicAuth.PasswordFile != "") {
 		return fmt.Errorf("at most one of basic_auth password & password_file must be configured")
 	}
+	if len(h.ProxyConnectHeader) > 0 && !h.ProxyFromEnvironment && h.ProxyURL.URL == nil {
+		return fmt.Errorf("if proxy_connect_header is configured, proxy_url or proxy_from_environment must also be configured")
+	}
+	if h.ProxyFromEnvironment && h.ProxyURL.URL != nil {
+		return fmt.Errorf("if proxy_from_environment is configured, proxy_url must not be configured")
+	}
+	if h.ProxyFromEnvironment && h.NoProxy != "" {
+		return fmt.Errorf("if proxy_from_environment is configured, no_proxy must not be configured")
+	}
+	if h.ProxyURL.URL == nil && h.NoProxy != "" {
+		return fmt.Errorf("if no_proxy is configured, proxy_url must also be configured")
+	}
 	if h.Authorization != nil {
 		if len(h.BearerToken) > 0 || len(h.BearerTokenFile) > 0 {
 			return fmt.Errorf("authorization is not compatible with bearer_token & bearer_token_file")
@@ -120,11 +135,31 @@ func (h *HTTPClientConfig) Convert() *config.HTTPClientConfig {
 		FollowRedirects: h.FollowRedirects,
 		EnableHTTP2:     h.EnableHTTP2,
 		ProxyConfig: config.ProxyConfig{
-			ProxyURL: h.ProxyURL.Convert(),
+			ProxyURL:             h.ProxyURL.Convert(),
+			NoProxy:              h.NoProxy,
+			ProxyFromEnvironment: h.ProxyFromEnvironment,
+			ProxyConnectHeader:   convertHeader(h.ProxyConnectHeader),
 		},
 	}
 }
 
+// convertHeader converts a header map to the native Prometheus type.
+func convertHeader(header map[string][]rivertypes.Secret) config.Header {
+	if len(header) == 0 {
+		return nil
+	}
+
+	res := make(config.Header, len(header))
+	for name, values := range header {
+		secrets := make([]config.Secret, 0, len(values))
+		for _, v := range values {
+			secrets = append(secrets, config.Secret(v))
+		}
+		res[name] = secrets
+	}
+	return res
+}
+
 // Clone creates a shallow clone of h.
 func CloneDefaultHTTPClientConfig() *HTTPClientConfig {
 	clone := DefaultHTTPClientConfig
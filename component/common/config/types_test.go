@@ -212,3 +212,33 @@ func TestHTTPClientBadConfig(t *testing.T) {
 	err := river.Unmarshal([]byte(exampleRiverConfig), &httpClientConfig)
 	require.ErrorContains(t, err, "at most one of bearer_token & bearer_token_file must be configured")
 }
+
+func TestHTTPClientConfigProxy(t *testing.T) {
+	var exampleRiverConfig = `
+	proxy_url = "http://0.0.0.0:11111"
+	no_proxy = "localhost,10.0.0.0/8"
+	proxy_connect_header = {
+		"Proxy-Authorization" = ["Basic dXNlcjpwYXNz"],
+	}
+`
+
+	var httpClientConfig HTTPClientConfig
+	err := river.Unmarshal([]byte(exampleRiverConfig), &httpClientConfig)
+	require.NoError(t, err)
+
+	converted := httpClientConfig.Convert()
+	require.NoError(t, converted.Validate())
+	require.Equal(t, "localhost,10.0.0.0/8", converted.NoProxy)
+	require.Equal(t, "Basic dXNlcjpwYXNz", string(converted.ProxyConnectHeader["Proxy-Authorization"][0]))
+}
+
+func TestHTTPClientConfigProxyFromEnvironment(t *testing.T) {
+	var exampleRiverConfig = `
+	proxy_from_environment = true
+	proxy_url = "http://0.0.0.0:11111"
+`
+
+	var httpClientConfig HTTPClientConfig
+	err := river.Unmarshal([]byte(exampleRiverConfig), &httpClientConfig)
+	require.ErrorContains(t, err, "if proxy_from_environment is configured, proxy_url must not be configured")
+}
@@ -0,0 +1,81 @@
+// Package errlog provides a bounded log of recent errors which components can
+// expose through their debug information, so that operators can see recent
+// failures without searching through logs.
+package errlog
+
+import (
+	"sync"
+	"time"
+)
+
+// Entry is a single error recorded in a Log.
+type Entry struct {
+	Time    time.Time `river:"time,attr"`
+	Message string    `river:"message,attr"`
+}
+
+// Log holds the most recent errors recorded to it, evicting the oldest error
+// once it's full. Log is safe for concurrent use. The zero value is not
+// usable; create a Log with New.
+type Log struct {
+	mut     sync.Mutex
+	entries []Entry // Ring buffer of entries.
+	next    int     // Index in entries to write the next entry to.
+	full    bool    // Whether entries has wrapped around.
+	evicted uint64  // Number of entries evicted from the log.
+	now     func() time.Time
+}
+
+// New creates a new Log which holds up to size errors. New panics if size
+// is less than 1.
+func New(size int) *Log {
+	if size < 1 {
+		panic("errlog: size must be at least 1")
+	}
+	return &Log{
+		entries: make([]Entry, size),
+		now:     time.Now,
+	}
+}
+
+// Record records err to the log, evicting the oldest error if the log is
+// full. Record does nothing if err is nil.
+func (l *Log) Record(err error) {
+	if err == nil {
+		return
+	}
+
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	if l.full {
+		l.evicted++
+	}
+	l.entries[l.next] = Entry{Time: l.now(), Message: err.Error()}
+	l.next = (l.next + 1) % len(l.entries)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// Entries returns the recorded errors, oldest first.
+func (l *Log) Entries() []Entry {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	if !l.full {
+		return append([]Entry(nil), l.entries[:l.next]...)
+	}
+
+	res := make([]Entry, 0, len(l.entries))
+	res = append(res, l.entries[l.next:]...)
+	return append(res, l.entries[:l.next]...)
+}
+
+// Evicted returns the number of errors which were evicted from the log to
+// make room for newer ones.
+func (l *Log) Evicted() uint64 {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+	return l.evicted
+}
@@ -0,0 +1,84 @@
+package errlog
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/agent/pkg/river"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLog(t *testing.T) {
+	l := New(3)
+
+	var now time.Time
+	l.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	require.Empty(t, l.Entries())
+
+	l.Record(nil)
+	require.Empty(t, l.Entries(), "nil errors must not be recorded")
+
+	l.Record(errors.New("error 1"))
+	l.Record(errors.New("error 2"))
+	require.Equal(t, []string{"error 1", "error 2"}, messages(l.Entries()))
+	require.Zero(t, l.Evicted())
+
+	l.Record(errors.New("error 3"))
+	l.Record(errors.New("error 4"))
+	l.Record(errors.New("error 5"))
+	require.Equal(t, []string{"error 3", "error 4", "error 5"}, messages(l.Entries()))
+	require.Equal(t, uint64(2), l.Evicted())
+
+	entries := l.Entries()
+	for i := 1; i < len(entries); i++ {
+		require.True(t, entries[i].Time.After(entries[i-1].Time), "entries must be ordered oldest first")
+	}
+}
+
+func TestLog_Concurrent(t *testing.T) {
+	l := New(10)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.Record(fmt.Errorf("error %d-%d", i, j))
+				_ = l.Entries()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	require.Len(t, l.Entries(), 10)
+	require.Equal(t, uint64(990), l.Evicted())
+}
+
+func TestEntry_River(t *testing.T) {
+	type debugInfo struct {
+		RecentErrors []Entry `river:"recent_error,block,optional"`
+	}
+
+	l := New(1)
+	l.Record(errors.New("failed to decode record"))
+
+	bb, err := river.Marshal(debugInfo{RecentErrors: l.Entries()})
+	require.NoError(t, err)
+	require.Contains(t, string(bb), `message = "failed to decode record"`)
+}
+
+func messages(entries []Entry) []string {
+	res := make([]string, 0, len(entries))
+	for _, e := range entries {
+		res = append(res, e.Message)
+	}
+	return res
+}
@@ -0,0 +1,45 @@
+// Package metrics provides a small instrumentation abstraction which
+// components can use instead of constructing Prometheus collectors directly.
+// This allows the backend receiving metrics to be swapped, for example for a
+// Spy in tests.
+package metrics
+
+// Counter is a metric which can only increase.
+type Counter interface {
+	// Inc increments the counter by 1.
+	Inc()
+	// Add adds v to the counter. v must not be negative.
+	Add(v float64)
+}
+
+// Gauge is a metric which can increase and decrease.
+type Gauge interface {
+	// Set sets the gauge to v.
+	Set(v float64)
+	// Inc increments the gauge by 1.
+	Inc()
+	// Dec decrements the gauge by 1.
+	Dec()
+	// Add adds v to the gauge. v may be negative.
+	Add(v float64)
+}
+
+// Histogram samples observations and counts them in buckets.
+type Histogram interface {
+	// Observe adds a single observation to the histogram.
+	Observe(v float64)
+}
+
+// Factory creates metrics. Metrics are identified by name; requesting a
+// metric with a name which was already requested returns a metric backed by
+// the same series. An error is returned if the metric can't be created, such
+// as when the name is already used by a metric of a different type.
+type Factory interface {
+	// Counter returns a counter with the given name and help text.
+	Counter(name, help string) (Counter, error)
+	// Gauge returns a gauge with the given name and help text.
+	Gauge(name, help string) (Gauge, error)
+	// Histogram returns a histogram with the given name and help text. If
+	// buckets is empty, the backend's default buckets are used.
+	Histogram(name, help string, buckets []float64) (Histogram, error)
+}
@@ -0,0 +1,89 @@
+package metrics_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grafana/agent/component/common/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusFactory(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	f := metrics.NewPrometheusFactory(reg)
+
+	c, err := f.Counter("requests_total", "Total requests.")
+	require.NoError(t, err)
+	c.Add(2)
+	// Requesting the same counter again must reuse the registered collector.
+	c, err = f.Counter("requests_total", "Total requests.")
+	require.NoError(t, err)
+	c.Inc()
+
+	g, err := f.Gauge("in_flight", "In-flight requests.")
+	require.NoError(t, err)
+	g.Set(5)
+	g.Dec()
+
+	h, err := f.Histogram("latency_seconds", "Request latency.", []float64{1})
+	require.NoError(t, err)
+	h.Observe(0.5)
+
+	// A name which is already used by a metric of another type can't be
+	// reused, even with the same help text.
+	_, err = f.Gauge("requests_total", "Total requests.")
+	require.Error(t, err)
+	_, err = f.Counter("in_flight", "In-flight requests.")
+	require.Error(t, err)
+	_, err = f.Histogram("in_flight", "In-flight requests.", []float64{1})
+	require.Error(t, err)
+
+	expect := `
+# HELP in_flight In-flight requests.
+# TYPE in_flight gauge
+in_flight 4
+# HELP latency_seconds Request latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{le="1"} 1
+latency_seconds_bucket{le="+Inf"} 1
+latency_seconds_sum 0.5
+latency_seconds_count 1
+# HELP requests_total Total requests.
+# TYPE requests_total counter
+requests_total 3
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expect)))
+}
+
+func TestSpy(t *testing.T) {
+	var spy metrics.Spy
+
+	c, err := spy.Counter("requests_total", "")
+	require.NoError(t, err)
+	c.Inc()
+	c.Add(2)
+
+	g, err := spy.Gauge("in_flight", "")
+	require.NoError(t, err)
+	g.Set(5)
+	g.Dec()
+
+	h, err := spy.Histogram("latency_seconds", "", nil)
+	require.NoError(t, err)
+	h.Observe(0.25)
+	h.Observe(1.5)
+
+	require.Equal(t, 3.0, spy.Value("requests_total"))
+	require.Equal(t, 4.0, spy.Value("in_flight"))
+	require.Equal(t, 0.0, spy.Value("missing"))
+	require.Equal(t, []float64{0.25, 1.5}, spy.Observations("latency_seconds"))
+
+	_, err = spy.Counter("in_flight", "")
+	require.Error(t, err)
+	_, err = spy.Gauge("requests_total", "")
+	require.Error(t, err)
+	_, err = spy.Histogram("requests_total", "", nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// prometheusFactory is a Factory which registers Prometheus collectors.
+type prometheusFactory struct {
+	reg prometheus.Registerer
+}
+
+var _ Factory = (*prometheusFactory)(nil)
+
+// NewPrometheusFactory returns a Factory which creates Prometheus collectors
+// and registers them to reg. Components typically pass the Registerer from
+// their component.Options.
+func NewPrometheusFactory(reg prometheus.Registerer) Factory {
+	return &prometheusFactory{reg: reg}
+}
+
+func (f *prometheusFactory) Counter(name, help string) (Counter, error) {
+	c := prometheus.NewCounter(prometheus.CounterOpts{Name: name, Help: help})
+	return register(f.reg, c)
+}
+
+func (f *prometheusFactory) Gauge(name, help string) (Gauge, error) {
+	g := prometheus.NewGauge(prometheus.GaugeOpts{Name: name, Help: help})
+	return register(f.reg, g)
+}
+
+func (f *prometheusFactory) Histogram(name, help string, buckets []float64) (Histogram, error) {
+	h := prometheus.NewHistogram(prometheus.HistogramOpts{Name: name, Help: help, Buckets: buckets})
+	return register(f.reg, h)
+}
+
+// register registers c to reg. If an identical collector was already
+// registered, the existing collector is returned instead.
+//
+// The existing collector is compared by the kind of metric it writes rather
+// than by interface: a Prometheus gauge also satisfies prometheus.Counter, and
+// must not be returned for a counter.
+func register[T interface {
+	prometheus.Metric
+	prometheus.Collector
+}](reg prometheus.Registerer, c T) (T, error) {
+	var zero T
+	if err := reg.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if !errors.As(err, &are) {
+			return zero, err
+		}
+		existing, ok := are.ExistingCollector.(T)
+		if !ok || metricKind(existing) != metricKind(c) {
+			return zero, fmt.Errorf("metric is already registered as a %s", metricKind(are.ExistingCollector))
+		}
+		return existing, nil
+	}
+	return c, nil
+}
+
+// metricKind returns the kind of metric written by c, or "collector" if c
+// isn't a single counter, gauge, or histogram.
+func metricKind(c prometheus.Collector) string {
+	m, ok := c.(prometheus.Metric)
+	if !ok {
+		return "collector"
+	}
+	var pb dto.Metric
+	if err := m.Write(&pb); err != nil {
+		return "collector"
+	}
+	switch {
+	case pb.Counter != nil:
+		return "counter"
+	case pb.Gauge != nil:
+		return "gauge"
+	case pb.Histogram != nil:
+		return "histogram"
+	default:
+		return "collector"
+	}
+}
@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Spy is a Factory which records every update made to its metrics in memory
+// so that tests can make assertions about them. The zero value is ready for
+// use.
+type Spy struct {
+	mut          sync.Mutex
+	kinds        map[string]string // Kind of metric requested for each name.
+	values       map[string]float64
+	observations map[string][]float64
+}
+
+var _ Factory = (*Spy)(nil)
+
+// Counter implements Factory.
+func (s *Spy) Counter(name, _ string) (Counter, error) {
+	if err := s.claim(name, "counter"); err != nil {
+		return nil, err
+	}
+	return &spyMetric{spy: s, name: name}, nil
+}
+
+// Gauge implements Factory.
+func (s *Spy) Gauge(name, _ string) (Gauge, error) {
+	if err := s.claim(name, "gauge"); err != nil {
+		return nil, err
+	}
+	return &spyMetric{spy: s, name: name}, nil
+}
+
+// Histogram implements Factory.
+func (s *Spy) Histogram(name, _ string, _ []float64) (Histogram, error) {
+	if err := s.claim(name, "histogram"); err != nil {
+		return nil, err
+	}
+	return &spyMetric{spy: s, name: name}, nil
+}
+
+// claim records name as a metric of the given kind, failing if the name was
+// already requested as a different kind of metric.
+func (s *Spy) claim(name, kind string) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	if s.kinds == nil {
+		s.kinds = make(map[string]string)
+	}
+	if existing, ok := s.kinds[name]; ok && existing != kind {
+		return fmt.Errorf("metric %q is already registered as a %s", name, existing)
+	}
+	s.kinds[name] = kind
+	return nil
+}
+
+// Value returns the current value of the counter or gauge with the given
+// name. Value returns 0 for metrics which were never updated.
+func (s *Spy) Value(name string) float64 {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.values[name]
+}
+
+// Observations returns a copy of the observations made to the histogram with
+// the given name, in the order they were made.
+func (s *Spy) Observations(name string) []float64 {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return append([]float64(nil), s.observations[name]...)
+}
+
+func (s *Spy) add(name string, v float64) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	if s.values == nil {
+		s.values = make(map[string]float64)
+	}
+	s.values[name] += v
+}
+
+func (s *Spy) set(name string, v float64) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	if s.values == nil {
+		s.values = make(map[string]float64)
+	}
+	s.values[name] = v
+}
+
+func (s *Spy) observe(name string, v float64) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	if s.observations == nil {
+		s.observations = make(map[string][]float64)
+	}
+	s.observations[name] = append(s.observations[name], v)
+}
+
+// spyMetric implements every metric type by forwarding updates to its Spy.
+type spyMetric struct {
+	spy  *Spy
+	name string
+}
+
+func (m *spyMetric) Inc()              { m.spy.add(m.name, 1) }
+func (m *spyMetric) Dec()              { m.spy.add(m.name, -1) }
+func (m *spyMetric) Add(v float64)     { m.spy.add(m.name, v) }
+func (m *spyMetric) Set(v float64)     { m.spy.set(m.name, v) }
+func (m *spyMetric) Observe(v float64) { m.spy.observe(m.name, v) }
@@ -0,0 +1,120 @@
+package net
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/go-kit/log"
+	"github.com/gorilla/mux"
+	"github.com/grafana/agent/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// OwnedServer manages a TargetServer whose lifecycle is tied to a component.
+// It is meant for components which must listen on a user-specified address,
+// such as components receiving pushed data from an external system.
+//
+// Components which only need to expose handlers should implement
+// component.HTTPComponent instead, which mounts the handlers on the shared
+// Flow HTTP server under the component's path.
+//
+// The server is started by the first call to Update and restarted whenever
+// Update is called with a different configuration. Run blocks until its
+// context is canceled and then shuts the server down.
+type OwnedServer struct {
+	logger           log.Logger
+	metricsNamespace string
+	mountRoute       func(router *mux.Router)
+	collector        *util.UncheckedCollector
+
+	mut    sync.Mutex
+	config *ServerConfig
+	server *TargetServer
+	closed bool
+}
+
+// NewOwnedServer creates a new OwnedServer. mountRoute is invoked to register
+// handlers every time the underlying server is (re)started. Server metrics
+// are registered to reg, prefixed by metricsNamespace.
+func NewOwnedServer(logger log.Logger, metricsNamespace string, reg prometheus.Registerer, mountRoute func(router *mux.Router)) (*OwnedServer, error) {
+	collector := util.NewUncheckedCollector(nil)
+	if err := reg.Register(collector); err != nil {
+		return nil, fmt.Errorf("failed to register server metrics: %w", err)
+	}
+
+	return &OwnedServer{
+		logger:           logger,
+		metricsNamespace: metricsNamespace,
+		mountRoute:       mountRoute,
+		collector:        collector,
+	}, nil
+}
+
+// Update applies config to the server, restarting it if config changed since
+// the last call. The server is not restarted when config is unchanged.
+func (s *OwnedServer) Update(config *ServerConfig) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if s.closed {
+		return fmt.Errorf("server is shut down")
+	}
+	if s.server != nil && reflect.DeepEqual(s.config, config) {
+		return nil
+	}
+	s.shutdown()
+
+	// The underlying server registers new metrics every time it is created. To
+	// avoid issues with re-registering metrics with the same name, a new
+	// registry is used for every server and exposed through an unchecked
+	// collector.
+	serverRegistry := prometheus.NewRegistry()
+	s.collector.SetCollector(serverRegistry)
+
+	srv, err := NewTargetServer(s.logger, s.metricsNamespace, serverRegistry, config)
+	if err != nil {
+		return fmt.Errorf("failed to create server: %w", err)
+	}
+	if err := srv.MountAndRun(s.mountRoute); err != nil {
+		return fmt.Errorf("failed to start server: %w", err)
+	}
+
+	s.server = srv
+	s.config = config
+	return nil
+}
+
+// Run blocks until ctx is canceled and then shuts down the server. Once Run
+// returns, subsequent calls to Update fail.
+func (s *OwnedServer) Run(ctx context.Context) error {
+	<-ctx.Done()
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	s.shutdown()
+	s.closed = true
+	return nil
+}
+
+// HTTPListenAddr returns the listen address of the running HTTP server, or an
+// empty string if the server isn't running.
+func (s *OwnedServer) HTTPListenAddr() string {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if s.server == nil {
+		return ""
+	}
+	return s.server.HTTPListenAddr()
+}
+
+// shutdown stops the current server, if any. s.mut must be held when calling
+// shutdown.
+func (s *OwnedServer) shutdown() {
+	if s.server != nil {
+		s.server.StopAndShutdown()
+		s.server = nil
+	}
+}
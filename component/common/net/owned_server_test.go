@@ -0,0 +1,76 @@
+package net
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/grafana/agent/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOwnedServer(t *testing.T) {
+	s, err := NewOwnedServer(util.TestLogger(t), "test_namespace", prometheus.NewRegistry(), func(router *mux.Router) {
+		router.Methods("GET").Path("/hello").Handler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+	})
+	require.NoError(t, err)
+	require.Empty(t, s.HTTPListenAddr(), "server must not run before Update")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runExited := make(chan error, 1)
+	go func() { runExited <- s.Run(ctx) }()
+
+	config := testOwnedServerConfig(time.Second)
+	require.NoError(t, s.Update(config))
+	initialServer := s.server
+	requireGet(t, s.HTTPListenAddr(), http.StatusOK)
+
+	// Updating with an identical config must not restart the server.
+	require.NoError(t, s.Update(testOwnedServerConfig(time.Second)))
+	require.Same(t, initialServer, s.server)
+
+	// Updating with a different config restarts the server.
+	require.NoError(t, s.Update(testOwnedServerConfig(2*time.Second)))
+	require.NotSame(t, initialServer, s.server)
+	newAddr := s.HTTPListenAddr()
+	requireGet(t, newAddr, http.StatusOK)
+
+	cancel()
+	select {
+	case err := <-runExited:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for Run to exit")
+	}
+
+	require.Empty(t, s.HTTPListenAddr())
+	_, err = http.Get(fmt.Sprintf("http://%s/hello", newAddr))
+	require.Error(t, err, "server must be shut down after Run exits")
+	require.Error(t, s.Update(config), "Update must fail after Run exits")
+}
+
+// testOwnedServerConfig returns a ServerConfig listening on random ports.
+// The graceful shutdown timeout is used to create distinct configs.
+func testOwnedServerConfig(gracefulShutdownTimeout time.Duration) *ServerConfig {
+	config := DefaultServerConfig()
+	config.HTTP.ListenAddress = "127.0.0.1"
+	config.HTTP.ListenPort = 0
+	config.GRPC.ListenAddress = "127.0.0.1"
+	config.GracefulShutdownTimeout = gracefulShutdownTimeout
+	return config
+}
+
+func requireGet(t *testing.T, addr string, expectStatus int) {
+	t.Helper()
+
+	res, err := http.Get(fmt.Sprintf("http://%s/hello", addr))
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, expectStatus, res.StatusCode)
+}
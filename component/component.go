@@ -116,6 +116,11 @@ type DebugComponent interface {
 }
 
 // HTTPComponent is an extension interface for components which contain their own HTTP handlers.
+//
+// Handlers of an HTTPComponent are served by the shared Flow HTTP server.
+// Components which must instead listen on a user-specified address, such as
+// components receiving data pushed by an external system, should manage their
+// own server with net.OwnedServer from component/common/net.
 type HTTPComponent interface {
 	Component
 
@@ -126,6 +131,44 @@ type HTTPComponent interface {
 	Handler() http.Handler
 }
 
+// ReadyComponent is an extension interface for components which need to warm
+// up before they can serve components that depend on them, such as components
+// which must complete an initial discovery or load before their exports are
+// meaningful.
+//
+// When starting the graph, the Flow controller calls WaitReady on every
+// ReadyComponent a component depends on, directly or indirectly, before
+// calling Run on the dependent component. Components which don't implement
+// ReadyComponent are considered ready as soon as they are built.
+type ReadyComponent interface {
+	Component
+
+	// WaitReady blocks until the component is ready or ctx is canceled.
+	// WaitReady may be called before Run and must be safe for calling
+	// concurrently.
+	//
+	// An error is returned if the component failed to become ready or ctx was
+	// canceled. Dependents are started regardless of the error so that a
+	// component which never becomes ready can't block the rest of the graph.
+	WaitReady(ctx context.Context) error
+}
+
+// DrainComponent is an extension interface for components which queue work
+// internally, such as components which buffer entries before sending them.
+//
+// When a DrainComponent is gracefully stopped, the Flow controller logs how
+// much work was pending when the stop began and how much of it the component
+// drained before Run returned.
+type DrainComponent interface {
+	Component
+
+	// PendingWork returns the number of queued items which haven't been
+	// processed yet. PendingWork is called concurrently with Run, including
+	// immediately before the component is asked to stop and after Run
+	// returns, and must be safe for calling concurrently.
+	PendingWork() int
+}
+
 // ClusteredComponent is an extension interface for components which implement
 // clustering-specific behavior.
 type ClusteredComponent interface {
@@ -0,0 +1,45 @@
+package component
+
+import (
+	"fmt"
+
+	"github.com/grafana/agent/pkg/river/token/builder"
+)
+
+// ConfigComponent is an optional extension interface for Components which
+// can report the configuration they are currently running with.
+//
+// Components should implement ConfigComponent when their effective
+// configuration differs from the Arguments they were last updated with, such
+// as when defaults are applied or values are resolved at runtime. Components
+// which don't implement ConfigComponent are assumed to run with their most
+// recent Arguments.
+type ConfigComponent interface {
+	Component
+
+	// CurrentConfig returns the effective configuration of the component. The
+	// result of CurrentConfig must be encodable to River like Arguments.
+	//
+	// CurrentConfig must be safe for calling concurrently.
+	CurrentConfig() Arguments
+}
+
+// MarshalConfig renders args as the body of a River block, suitable for
+// displaying the current configuration of a running component.
+//
+// The output is meant for display only, and can't always be unmarshaled back
+// into the Arguments type of the component: secrets and capsule values are
+// rendered as placeholders rather than their values.
+func MarshalConfig(args Arguments) (bb []byte, err error) {
+	defer func() {
+		// The builder panics on values it can't encode; report those as errors
+		// instead so a single component can't break the caller.
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to encode component config: %v", r)
+		}
+	}()
+
+	f := builder.NewFile()
+	f.Body().AppendFrom(args)
+	return f.Bytes(), nil
+}
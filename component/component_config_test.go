@@ -0,0 +1,82 @@
+package component_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/agent/component"
+	"github.com/grafana/agent/pkg/river"
+	"github.com/grafana/agent/pkg/river/rivertypes"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfigArguments struct {
+	Name     string            `river:"name,attr"`
+	Interval time.Duration     `river:"interval,attr,optional"`
+	Labels   map[string]string `river:"labels,attr,optional"`
+	Rule     []testConfigRule  `river:"rule,block,optional"`
+}
+
+type testConfigRule struct {
+	Action string `river:"action,attr"`
+}
+
+func TestMarshalConfig(t *testing.T) {
+	args := testConfigArguments{
+		Name:     "example",
+		Interval: time.Minute,
+		Labels:   map[string]string{"env": "prod"},
+		Rule:     []testConfigRule{{Action: "keep"}, {Action: "drop"}},
+	}
+
+	bb, err := component.MarshalConfig(args)
+	require.NoError(t, err)
+
+	expect := `name     = "example"
+interval = "1m0s"
+labels   = {
+	env = "prod",
+}
+
+rule {
+	action = "keep"
+}
+
+rule {
+	action = "drop"
+}`
+	require.Equal(t, expect, string(bb))
+
+	var actual testConfigArguments
+	require.NoError(t, river.Unmarshal(bb, &actual))
+	require.Equal(t, args, actual)
+}
+
+func TestMarshalConfig_Secret(t *testing.T) {
+	type secretArguments struct {
+		Username rivertypes.OptionalSecret `river:"username,attr"`
+		Password rivertypes.Secret         `river:"password,attr"`
+	}
+
+	args := secretArguments{
+		Username: rivertypes.OptionalSecret{Value: "admin"},
+		Password: rivertypes.Secret("hunter2"),
+	}
+
+	bb, err := component.MarshalConfig(args)
+	require.NoError(t, err)
+
+	expect := `username = "admin"
+password = (secret)`
+	require.Equal(t, expect, string(bb))
+	require.NotContains(t, string(bb), "hunter2")
+
+	// Secrets are rendered as placeholders, so the output doesn't round-trip.
+	var actual secretArguments
+	require.Error(t, river.Unmarshal(bb, &actual))
+}
+
+func TestMarshalConfig_Invalid(t *testing.T) {
+	_, err := component.MarshalConfig("not a struct")
+	require.Error(t, err)
+}
@@ -0,0 +1,68 @@
+package component
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Requirement is a feature of the host which a component needs in order to
+// run.
+type Requirement string
+
+const (
+	// RequirementDataPath indicates that the component needs a directory to
+	// store data in.
+	RequirementDataPath Requirement = "data_path"
+
+	// RequirementHTTPServer indicates that the component needs the shared HTTP
+	// server to receive traffic.
+	RequirementHTTPServer Requirement = "http_server"
+
+	// RequirementClustering indicates that the component needs a clusterer to
+	// distribute work between agents.
+	RequirementClustering Requirement = "clustering"
+)
+
+// satisfied reports whether opts satisfy the requirement r.
+func (r Requirement) satisfied(opts Options) bool {
+	switch r {
+	case RequirementDataPath:
+		return opts.DataPath != ""
+	case RequirementHTTPServer:
+		return opts.HTTPListenAddr != ""
+	case RequirementClustering:
+		return opts.Clusterer != nil
+	default:
+		return false
+	}
+}
+
+// RequirementsError is returned by CheckRequirements when a component has
+// requirements which are not satisfied by the host.
+type RequirementsError struct {
+	Unmet []Requirement
+}
+
+// Error implements error.
+func (e RequirementsError) Error() string {
+	names := make([]string, 0, len(e.Unmet))
+	for _, r := range e.Unmet {
+		names = append(names, string(r))
+	}
+	return fmt.Sprintf("component requires features which are not available: %s", strings.Join(names, ", "))
+}
+
+// CheckRequirements returns a RequirementsError if any of the Requirements
+// declared by the registration are not satisfied by opts.
+func CheckRequirements(reg Registration, opts Options) error {
+	var unmet []Requirement
+	for _, r := range reg.Requirements {
+		if !r.satisfied(opts) {
+			unmet = append(unmet, r)
+		}
+	}
+	if len(unmet) > 0 {
+		return RequirementsError{Unmet: unmet}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package component_test
+
+import (
+	"testing"
+
+	"github.com/grafana/agent/component"
+	"github.com/grafana/agent/pkg/cluster"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRequirements(t *testing.T) {
+	reg := component.Registration{
+		Name: "test.requirements",
+		Requirements: []component.Requirement{
+			component.RequirementDataPath,
+			component.RequirementHTTPServer,
+			component.RequirementClustering,
+		},
+	}
+
+	t.Run("all requirements met", func(t *testing.T) {
+		opts := component.Options{
+			DataPath:       t.TempDir(),
+			HTTPListenAddr: "127.0.0.1:12345",
+			Clusterer:      &cluster.Clusterer{},
+		}
+		require.NoError(t, component.CheckRequirements(reg, opts))
+	})
+
+	t.Run("unmet requirements", func(t *testing.T) {
+		opts := component.Options{DataPath: t.TempDir()}
+
+		err := component.CheckRequirements(reg, opts)
+		require.EqualError(t, err, "component requires features which are not available: http_server, clustering")
+
+		var reqErr component.RequirementsError
+		require.ErrorAs(t, err, &reqErr)
+		require.Equal(t, []component.Requirement{
+			component.RequirementHTTPServer,
+			component.RequirementClustering,
+		}, reqErr.Unmet)
+	})
+
+	t.Run("no requirements", func(t *testing.T) {
+		require.NoError(t, component.CheckRequirements(component.Registration{}, component.Options{}))
+	})
+}
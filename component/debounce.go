@@ -0,0 +1,82 @@
+package component
+
+import (
+	"sync"
+	"time"
+)
+
+// StateChangeDebouncer coalesces rapid calls to a component's OnStateChange
+// function. Components which update their exports frequently, such as once
+// per request, can use it to avoid queueing the components depending on them
+// for re-evaluation on every update.
+//
+// The first update after a quiet period starts a window of the configured
+// length; only the most recent exports given during the window are forwarded,
+// once the window ends. Debouncing trades latency for fewer evaluations:
+// dependent components may observe new exports up to one window late, but
+// never more than once per window.
+type StateChangeDebouncer struct {
+	onStateChange func(e Exports)
+	window        time.Duration
+
+	mut     sync.Mutex
+	pending Exports
+	timer   *time.Timer
+	stopped bool
+}
+
+// NewStateChangeDebouncer returns a StateChangeDebouncer which forwards
+// exports to onStateChange at most once per window.
+func NewStateChangeDebouncer(onStateChange func(e Exports), window time.Duration) *StateChangeDebouncer {
+	return &StateChangeDebouncer{
+		onStateChange: onStateChange,
+		window:        window,
+	}
+}
+
+// OnStateChange queues e to be forwarded at the end of the current window,
+// replacing any exports queued earlier in the window. It has the same
+// signature as Options.OnStateChange so it can be used in its place.
+func (d *StateChangeDebouncer) OnStateChange(e Exports) {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+
+	if d.stopped {
+		return
+	}
+
+	d.pending = e
+	if d.timer == nil {
+		d.timer = time.AfterFunc(d.window, d.flush)
+	}
+}
+
+// Stop forwards any pending exports immediately and stops the debouncer.
+// Calls to OnStateChange after Stop are ignored.
+func (d *StateChangeDebouncer) Stop() {
+	d.mut.Lock()
+	d.stopped = true
+	if d.timer != nil && !d.timer.Stop() {
+		// The timer already fired and flush is running or about to run; it
+		// will forward the pending exports.
+		d.mut.Unlock()
+		return
+	}
+	d.mut.Unlock()
+
+	d.flush()
+}
+
+// flush forwards the pending exports, if any.
+func (d *StateChangeDebouncer) flush() {
+	d.mut.Lock()
+	pending := d.pending
+	hasPending := d.timer != nil
+	d.pending = nil
+	d.timer = nil
+	d.mut.Unlock()
+
+	if hasPending {
+		d.onStateChange(pending)
+	}
+}
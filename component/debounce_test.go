@@ -0,0 +1,63 @@
+package component_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/agent/component"
+	"github.com/stretchr/testify/require"
+)
+
+type debounceRecorder struct {
+	mut   sync.Mutex
+	calls []component.Exports
+}
+
+func (r *debounceRecorder) OnStateChange(e component.Exports) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	r.calls = append(r.calls, e)
+}
+
+func (r *debounceRecorder) Calls() []component.Exports {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	return append([]component.Exports(nil), r.calls...)
+}
+
+func TestStateChangeDebouncer(t *testing.T) {
+	var rec debounceRecorder
+	d := component.NewStateChangeDebouncer(rec.OnStateChange, 50*time.Millisecond)
+	defer d.Stop()
+
+	for i := 0; i < 100; i++ {
+		d.OnStateChange(i)
+	}
+
+	require.Eventually(t, func() bool { return len(rec.Calls()) == 1 }, time.Second, 5*time.Millisecond)
+	require.Equal(t, []component.Exports{99}, rec.Calls(), "only the latest exports should be forwarded")
+
+	// A new update after the window starts a new window.
+	d.OnStateChange(100)
+	require.Eventually(t, func() bool { return len(rec.Calls()) == 2 }, time.Second, 5*time.Millisecond)
+	require.Equal(t, []component.Exports{99, 100}, rec.Calls())
+}
+
+func TestStateChangeDebouncer_Stop(t *testing.T) {
+	var rec debounceRecorder
+	d := component.NewStateChangeDebouncer(rec.OnStateChange, time.Hour)
+
+	d.OnStateChange("first")
+	d.OnStateChange("second")
+	require.Empty(t, rec.Calls())
+
+	// Stop flushes pending exports without waiting for the window to end.
+	d.Stop()
+	require.Equal(t, []component.Exports{"second"}, rec.Calls())
+
+	// Updates after Stop are ignored.
+	d.OnStateChange("third")
+	d.Stop()
+	require.Equal(t, []component.Exports{"second"}, rec.Calls())
+}
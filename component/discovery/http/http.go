@@ -0,0 +1,84 @@
+// Package http implements a discovery.http component.
+package http
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/agent/component"
+	"github.com/grafana/agent/component/common/config"
+	"github.com/grafana/agent/component/discovery"
+	"github.com/prometheus/common/model"
+	prom_http "github.com/prometheus/prometheus/discovery/http"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:    "discovery.http",
+		Args:    Arguments{},
+		Exports: discovery.Exports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// Arguments configures the discovery.http component.
+type Arguments struct {
+	URL              config.URL              `river:"url,attr"`
+	RefreshInterval  time.Duration           `river:"refresh_interval,attr,optional"`
+	HTTPClientConfig config.HTTPClientConfig `river:",squash"`
+}
+
+// DefaultArguments holds default settings for Arguments.
+var DefaultArguments = Arguments{
+	RefreshInterval:  time.Minute,
+	HTTPClientConfig: config.DefaultHTTPClientConfig,
+}
+
+// UnmarshalRiver implements river.Unmarshaler and applies default settings.
+func (args *Arguments) UnmarshalRiver(f func(interface{}) error) error {
+	*args = DefaultArguments
+	type arguments Arguments
+	if err := f((*arguments)(args)); err != nil {
+		return err
+	}
+	return args.Validate()
+}
+
+// Validate validates the arguments.
+func (args *Arguments) Validate() error {
+	if args.URL.URL == nil {
+		return fmt.Errorf("url must not be empty")
+	}
+	if args.URL.Scheme != "http" && args.URL.Scheme != "https" {
+		return fmt.Errorf("url must use the http or https scheme")
+	}
+	if args.URL.Host == "" {
+		return fmt.Errorf("host is missing in url")
+	}
+	if args.RefreshInterval <= 0 {
+		return fmt.Errorf("refresh_interval must be greater than 0")
+	}
+
+	// We must explicitly Validate because HTTPClientConfig is squashed and it won't run otherwise
+	return args.HTTPClientConfig.Validate()
+}
+
+// Convert converts Arguments to the Prometheus SD type.
+func (args *Arguments) Convert() *prom_http.SDConfig {
+	return &prom_http.SDConfig{
+		URL:              args.URL.String(),
+		RefreshInterval:  model.Duration(args.RefreshInterval),
+		HTTPClientConfig: *args.HTTPClientConfig.Convert(),
+	}
+}
+
+// New returns a new instance of a discovery.http component.
+func New(opts component.Options, args Arguments) (component.Component, error) {
+	return discovery.New(opts, args, func(args component.Arguments) (discovery.Discoverer, error) {
+		newArgs := args.(Arguments)
+		return prom_http.NewDiscovery(newArgs.Convert(), opts.Logger, nil)
+	})
+}
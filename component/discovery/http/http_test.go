@@ -0,0 +1,97 @@
+package http
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/agent/pkg/river"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRiverUnmarshal(t *testing.T) {
+	riverCfg := `
+		url = "https://example.com/targets"
+		refresh_interval = "30s"
+
+		basic_auth {
+			username = "user"
+			password = "pass"
+		}
+	`
+
+	var args Arguments
+	require.NoError(t, river.Unmarshal([]byte(riverCfg), &args))
+
+	require.Equal(t, "https://example.com/targets", args.URL.String())
+	require.Equal(t, 30*time.Second, args.RefreshInterval)
+	require.Equal(t, "user", args.HTTPClientConfig.BasicAuth.Username)
+	require.True(t, args.HTTPClientConfig.FollowRedirects)
+	require.True(t, args.HTTPClientConfig.EnableHTTP2)
+}
+
+func TestRiverUnmarshal_Defaults(t *testing.T) {
+	var args Arguments
+	require.NoError(t, river.Unmarshal([]byte(`url = "http://example.com/targets"`), &args))
+	require.Equal(t, DefaultArguments.RefreshInterval, args.RefreshInterval)
+}
+
+func TestValidate(t *testing.T) {
+	tt := []struct {
+		name      string
+		riverCfg  string
+		expectErr string
+	}{
+		{
+			name:      "unsupported scheme",
+			riverCfg:  `url = "ftp://example.com/targets"`,
+			expectErr: "url must use the http or https scheme",
+		},
+		{
+			name:      "missing host",
+			riverCfg:  `url = "http:///targets"`,
+			expectErr: "host is missing in url",
+		},
+		{
+			name: "invalid refresh interval",
+			riverCfg: `
+				url = "http://example.com/targets"
+				refresh_interval = "0s"
+			`,
+			expectErr: "refresh_interval must be greater than 0",
+		},
+		{
+			name: "multiple auth methods",
+			riverCfg: `
+				url = "http://example.com/targets"
+				bearer_token = "token"
+				basic_auth {
+					username = "user"
+				}
+			`,
+			expectErr: "at most one of basic_auth, oauth2, bearer_token & bearer_token_file must be configured",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			err := river.Unmarshal([]byte(tc.riverCfg), &args)
+			require.ErrorContains(t, err, tc.expectErr)
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	var args Arguments
+	require.NoError(t, river.Unmarshal([]byte(`
+		url = "https://example.com/targets"
+		refresh_interval = "5m"
+		bearer_token_file = "/etc/token"
+	`), &args))
+
+	sdConfig := args.Convert()
+	require.Equal(t, "https://example.com/targets", sdConfig.URL)
+	require.Equal(t, model.Duration(5*time.Minute), sdConfig.RefreshInterval)
+	require.Equal(t, "/etc/token", sdConfig.HTTPClientConfig.Authorization.CredentialsFile)
+}
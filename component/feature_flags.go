@@ -0,0 +1,25 @@
+package component
+
+// FeatureFlags holds the experimental behaviors enabled for components, keyed
+// by flag name. Flags let components ship experimental code paths without
+// committing to a config schema for them.
+//
+// Flag names should be prefixed by the name of the component which consults
+// them, such as "loki.source.api.streaming_decode".
+//
+// Flags follow a lifecycle:
+//
+//   - Experimental: the behavior is off unless its flag is enabled, and may
+//     change or be removed without notice.
+//   - Stable: the behavior becomes the default, or is exposed as a regular
+//     component argument. The flag is kept for at least one release and
+//     ignored, so configs enabling it keep working.
+//   - Removed: the flag is deleted. Enabling an unknown flag has no effect.
+//
+// A nil FeatureFlags has no flags enabled.
+type FeatureFlags map[string]bool
+
+// Enabled reports whether the flag with the given name is enabled.
+func (ff FeatureFlags) Enabled(name string) bool {
+	return ff[name]
+}
@@ -51,6 +51,9 @@ func NewModuleComponent(o component.Options) *ModuleComponent {
 			DataPath:       o.DataPath,
 			HTTPPathPrefix: o.HTTPPath,
 			HTTPListenAddr: o.HTTPListenAddr,
+			FeatureFlags:   o.FeatureFlags,
+
+			ComponentLogLevels: o.LogLevels,
 
 			OnExportsChange: func(exports map[string]any) {
 				o.OnStateChange(Exports{Exports: exports})
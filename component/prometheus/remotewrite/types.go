@@ -13,8 +13,10 @@ import (
 
 	types "github.com/grafana/agent/component/common/config"
 	"github.com/grafana/agent/pkg/river"
+	"github.com/grafana/agent/pkg/river/rivertypes"
 	common "github.com/prometheus/common/config"
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/common/sigv4"
 )
 
 // Defaults for config blocks.
@@ -47,6 +49,7 @@ var (
 	}
 
 	_ river.Unmarshaler = (*QueueOptions)(nil)
+	_ river.Unmarshaler = (*SigV4Config)(nil)
 )
 
 // Arguments represents the input state of the prometheus.remote_write
@@ -77,6 +80,7 @@ type EndpointOptions struct {
 	HTTPClientConfig     *types.HTTPClientConfig `river:",squash"`
 	QueueOptions         *QueueOptions           `river:"queue_config,block,optional"`
 	MetadataOptions      *MetadataOptions        `river:"metadata_config,block,optional"`
+	SigV4                *SigV4Config            `river:"sigv4,block,optional"`
 }
 
 func GetDefaultEndpointOptions() EndpointOptions {
@@ -101,12 +105,59 @@ func (r *EndpointOptions) UnmarshalRiver(f func(v interface{}) error) error {
 
 	// We must explicitly Validate because HTTPClientConfig is squashed and it won't run otherwise
 	if r.HTTPClientConfig != nil {
-		return r.HTTPClientConfig.Validate()
+		if err := r.HTTPClientConfig.Validate(); err != nil {
+			return err
+		}
+
+		httpClientAuthEnabled := r.HTTPClientConfig.BasicAuth != nil ||
+			r.HTTPClientConfig.Authorization != nil || r.HTTPClientConfig.OAuth2 != nil
+		if httpClientAuthEnabled && r.SigV4 != nil {
+			return fmt.Errorf("at most one of basic_auth, authorization, oauth2, & sigv4 must be configured")
+		}
 	}
 
 	return nil
 }
 
+// SigV4Config configures signing remote_write requests with AWS Signature
+// Version 4.
+type SigV4Config struct {
+	Region    string            `river:"region,attr,optional"`
+	AccessKey string            `river:"access_key,attr,optional"`
+	SecretKey rivertypes.Secret `river:"secret_key,attr,optional"`
+	Profile   string            `river:"profile,attr,optional"`
+	RoleARN   string            `river:"role_arn,attr,optional"`
+}
+
+// UnmarshalRiver implements river.Unmarshaler.
+func (s *SigV4Config) UnmarshalRiver(f func(v interface{}) error) error {
+	*s = SigV4Config{}
+
+	type config SigV4Config
+	if err := f((*config)(s)); err != nil {
+		return err
+	}
+
+	if (s.AccessKey == "") != (s.SecretKey == "") {
+		return fmt.Errorf("access_key and secret_key must both be provided if either is provided")
+	}
+	return nil
+}
+
+func (s *SigV4Config) toPrometheusType() *sigv4.SigV4Config {
+	if s == nil {
+		return nil
+	}
+
+	return &sigv4.SigV4Config{
+		Region:    s.Region,
+		AccessKey: s.AccessKey,
+		SecretKey: common.Secret(s.SecretKey),
+		Profile:   s.Profile,
+		RoleARN:   s.RoleARN,
+	}
+}
+
 // QueueOptions handles the low level queue config options for a remote_write
 type QueueOptions struct {
 	Capacity          int           `river:"capacity,attr,optional"`
@@ -224,7 +275,7 @@ func convertConfigs(cfg Arguments) (*config.Config, error) {
 			HTTPClientConfig: *rw.HTTPClientConfig.Convert(),
 			QueueConfig:      rw.QueueOptions.toPrometheusType(),
 			MetadataConfig:   rw.MetadataOptions.toPrometheusType(),
-			// TODO(rfratto): SigV4Config
+			SigV4Config:      rw.SigV4.toPrometheusType(),
 		})
 	}
 
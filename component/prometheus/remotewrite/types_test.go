@@ -53,3 +53,72 @@ func TestBadRiverConfig(t *testing.T) {
 	err := river.Unmarshal([]byte(exampleRiverConfig), &args)
 	require.ErrorContains(t, err, "at most one of bearer_token & bearer_token_file must be configured")
 }
+
+func TestRiverConfigSigV4(t *testing.T) {
+	var exampleRiverConfig = `
+		endpoint {
+			url = "http://0.0.0.0:11111/api/v1/write"
+
+			sigv4 {
+				region     = "us-east-1"
+				access_key = "access_key"
+				secret_key = "secret_key"
+			}
+		}
+`
+
+	var args Arguments
+	err := river.Unmarshal([]byte(exampleRiverConfig), &args)
+	require.NoError(t, err)
+
+	cfg, err := convertConfigs(args)
+	require.NoError(t, err)
+	require.Len(t, cfg.RemoteWriteConfigs, 1)
+	require.NotNil(t, cfg.RemoteWriteConfigs[0].SigV4Config)
+	require.Equal(t, "us-east-1", cfg.RemoteWriteConfigs[0].SigV4Config.Region)
+	require.Equal(t, "secret_key", string(cfg.RemoteWriteConfigs[0].SigV4Config.SecretKey))
+}
+
+func TestBadRiverConfigSigV4(t *testing.T) {
+	tt := []struct {
+		name   string
+		config string
+		expect string
+	}{
+		{
+			name: "missing secret_key",
+			config: `
+				endpoint {
+					url = "http://0.0.0.0:11111/api/v1/write"
+
+					sigv4 {
+						access_key = "access_key"
+					}
+				}
+			`,
+			expect: "access_key and secret_key must both be provided if either is provided",
+		},
+		{
+			name: "combined with another auth method",
+			config: `
+				endpoint {
+					url          = "http://0.0.0.0:11111/api/v1/write"
+					bearer_token = "token"
+
+					sigv4 {
+						region = "us-east-1"
+					}
+				}
+			`,
+			expect: "at most one of basic_auth, authorization, oauth2, & sigv4 must be configured",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			err := river.Unmarshal([]byte(tc.config), &args)
+			require.ErrorContains(t, err, tc.expect)
+		})
+	}
+}
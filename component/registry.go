@@ -81,6 +81,16 @@ type Options struct {
 	// component. Requests received by a component handler will have this already
 	// trimmed off.
 	HTTPPath string
+
+	// FeatureFlags holds the experimental behaviors enabled for the component.
+	// Components must only consult flags to gate experimental code paths.
+	FeatureFlags FeatureFlags
+
+	// LogLevels holds the per-component log level overrides of the controller
+	// running the component, keyed by global component ID. Components which
+	// run their own Flow controller, such as modules, must pass LogLevels to
+	// it.
+	LogLevels map[string]logging.Level
 }
 
 // Registration describes a single component.
@@ -128,6 +138,11 @@ type Registration struct {
 	// A component which does not expose exports must leave this set to nil.
 	Exports Exports
 
+	// Requirements lists the features of the host which the component needs
+	// in order to run. The Flow controller refuses to build the component if
+	// any requirement is unmet. See CheckRequirements.
+	Requirements []Requirement
+
 	// Build should construct a new component from an initial Arguments and set
 	// of options.
 	Build func(opts Options, args Arguments) (Component, error)
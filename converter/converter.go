@@ -4,8 +4,17 @@ package converter
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/grafana/agent/component"
+	"github.com/grafana/agent/converter/diag"
 	"github.com/grafana/agent/converter/internal/prometheusconvert"
+	"github.com/grafana/agent/pkg/river/ast"
+	"github.com/grafana/agent/pkg/river/parser"
+
+	// Register the components so the generated config can be verified against
+	// them.
+	_ "github.com/grafana/agent/component/all"
 )
 
 // Input represents the type of config file being fed into the converter.
@@ -16,6 +25,56 @@ const (
 	InputPrometheus Input = "prometheus"
 )
 
+// ConvertOptions customizes the output of a conversion.
+type ConvertOptions struct {
+	// Annotate adds a comment above each generated component naming the block
+	// of the input config it was converted from.
+	Annotate bool
+
+	// PreserveComments carries comments of the input config over to the
+	// generated components. Only comments attached to a Prometheus
+	// scrape_config or its job_name are preserved, and they're placed above
+	// the scrape_config's prometheus.scrape component.
+	PreserveComments bool
+
+	// Sections limits the output to the named sections of the input config.
+	// If Sections is empty, the whole config is converted. See ConvertSubset
+	// for the supported sections.
+	Sections []string
+
+	// ExpandEnv replaces ${VAR} references in the input config with the value
+	// of VAR before converting it, which is useful for templated config files.
+	// Values are looked up from Env, or from the process environment if Env is
+	// nil. Unresolved references are replaced with an empty string and
+	// reported as warnings.
+	//
+	// Bare $VAR references and references in relabel replacement and
+	// target_label values aren't expanded, since relabeling rules use them to
+	// refer to capture groups. $${VAR} is converted to a literal ${VAR}.
+	ExpandEnv bool
+
+	// Env holds the variables used by ExpandEnv.
+	Env map[string]string
+
+	// BaseDir is the directory relative paths of files included by the input
+	// config are resolved against, such as the files of a Prometheus
+	// scrape_config_files field. It's typically the directory containing the
+	// input config. If empty, the current working directory is used.
+	BaseDir string
+
+	// TargetVersion is the Grafana Agent release the generated config must be
+	// valid for, such as "v0.33.0". Features of the input which require a
+	// newer release are either converted differently or reported. If empty,
+	// the latest release is targeted.
+	TargetVersion string
+
+	// VerifyOutput checks the generated config before returning it. If the
+	// generated config can't be parsed, declares an unknown component, or
+	// declares the same component twice, an error diagnostic is returned
+	// alongside it.
+	VerifyOutput bool
+}
+
 // Convert generates a Grafana Agent Flow config given an input configuration
 // file.
 //
@@ -25,13 +84,96 @@ const (
 //
 // Note that not all functionality defined in the input configuration may have
 // an equivalent in Grafana Agent Flow. If the conversion could not complete
-// because of mismatched functionality, an error is returned with no resulting
-// config. If the conversion completed successfully but generated warnings, an
-// error is returned alongside the resulting config.
-func Convert(in []byte, kind Input) ([]byte, error) {
+// because of mismatched functionality, a critical diagnostic is returned with
+// no resulting config. If the conversion completed successfully but generated
+// warnings, the diagnostics are returned alongside the resulting config.
+func Convert(in []byte, kind Input) ([]byte, diag.Diagnostics) {
+	return ConvertWithOptions(in, kind, ConvertOptions{})
+}
+
+// ConvertSubset is like Convert but limits the output to the named sections of
+// the input config, which is useful for reviewing a large conversion in
+// parts. Diagnostics report which sections were skipped.
+//
+// For InputPrometheus, the supported sections are "scrape", which includes
+// the discovery and relabeling components feeding each scrape, and
+// "remote_write".
+func ConvertSubset(in []byte, kind Input, sections []string) ([]byte, diag.Diagnostics) {
+	return ConvertWithOptions(in, kind, ConvertOptions{Sections: sections})
+}
+
+// ConvertWithOptions is like Convert but allows customizing the output with
+// opts.
+func ConvertWithOptions(in []byte, kind Input, opts ConvertOptions) ([]byte, diag.Diagnostics) {
+	out, _, diags := ConvertWithSummary(in, kind, opts)
+	return out, diags
+}
+
+// ConvertWithSummary is like ConvertWithOptions but also returns a
+// ConversionSummary tallying how much of the input config was converted.
+func ConvertWithSummary(in []byte, kind Input, opts ConvertOptions) ([]byte, ConversionSummary, diag.Diagnostics) {
+	var (
+		out     []byte
+		summary ConversionSummary
+		diags   diag.Diagnostics
+	)
+
 	switch kind {
 	case InputPrometheus:
-		return prometheusconvert.Convert(in)
+		var promSummary prometheusconvert.Summary
+		out, promSummary, diags = prometheusconvert.ConvertWithSummary(in, prometheusconvert.Options{
+			Annotate:         opts.Annotate,
+			PreserveComments: opts.PreserveComments,
+			Sections:         opts.Sections,
+			ExpandEnv:        opts.ExpandEnv,
+			Env:              opts.Env,
+			BaseDir:          opts.BaseDir,
+			TargetVersion:    opts.TargetVersion,
+		})
+		summary = newConversionSummary(promSummary)
+	default:
+		diags.Add(diag.SeverityLevelCritical, diag.CodeUnrecognizedKind, fmt.Sprintf("unrecognized kind %q", kind))
+		return nil, summary, diags
+	}
+
+	if opts.VerifyOutput && out != nil {
+		diags = append(diags, verifyOutput(out)...)
+	}
+	return out, summary, diags
+}
+
+// verifyOutput returns error diagnostics if out can't be loaded as a Flow
+// config: if it doesn't parse, if it declares a component which doesn't
+// exist, or if two of its components share an ID.
+func verifyOutput(out []byte) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	f, err := parser.ParseFile("converted.river", out)
+	if err != nil {
+		diags.Add(diag.SeverityLevelError, diag.CodeInvalidOutput, fmt.Sprintf("generated Flow config does not parse: %s", err))
+		return diags
+	}
+
+	seen := make(map[string]bool)
+	for _, stmt := range f.Body {
+		block, ok := stmt.(*ast.BlockStmt)
+		if !ok {
+			continue
+		}
+
+		name := strings.Join(block.Name, ".")
+		if _, exists := component.Get(name); !exists {
+			diags.Add(diag.SeverityLevelError, diag.CodeInvalidOutput, fmt.Sprintf("generated Flow config declares unknown component %q", name))
+		}
+
+		id := name
+		if block.Label != "" {
+			id += "." + block.Label
+		}
+		if seen[id] {
+			diags.Add(diag.SeverityLevelError, diag.CodeInvalidOutput, fmt.Sprintf("generated Flow config declares component %q more than once", id))
+		}
+		seen[id] = true
 	}
-	return nil, fmt.Errorf("unrecognized kind %q", kind)
+	return diags
 }
@@ -0,0 +1,109 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/grafana/agent/converter/diag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertWithOptions_VerifyOutput(t *testing.T) {
+	in := []byte(`
+scrape_configs:
+  - job_name: prometheus
+    static_configs:
+      - targets: ["localhost:9090"]
+remote_write:
+  - url: http://localhost:9009/api/prom/push
+`)
+
+	out, diags := ConvertWithOptions(in, InputPrometheus, ConvertOptions{VerifyOutput: true})
+	require.False(t, diags.HasErrors(), diags.Error())
+	require.NotEmpty(t, out)
+}
+
+func TestVerifyOutput(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		diags := verifyOutput([]byte(`prometheus.scrape "default" {
+	targets = [{"__address__" = "localhost:9090"}]
+}
+`))
+		require.Empty(t, diags)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		diags := verifyOutput([]byte(`prometheus.scrape "default" {
+	targets = [{"__address__" = "localhost:9090"}
+`))
+		require.Len(t, diags, 1)
+		require.Equal(t, diag.SeverityLevelError, diags[0].Severity)
+		require.Equal(t, diag.CodeInvalidOutput, diags[0].Code)
+	})
+
+	t.Run("duplicate component", func(t *testing.T) {
+		diags := verifyOutput([]byte(`discovery.kubernetes "a_1" {
+	role = "pod"
+}
+
+discovery.kubernetes "a_1" {
+	role = "node"
+}
+`))
+		require.Len(t, diags, 1)
+		require.Equal(t, diag.CodeInvalidOutput, diags[0].Code)
+		require.Contains(t, diags[0].Summary, `"discovery.kubernetes.a_1" more than once`)
+	})
+
+	t.Run("unknown component", func(t *testing.T) {
+		diags := verifyOutput([]byte(`discovery.unknown "default" {
+	role = "pod"
+}
+`))
+		require.Len(t, diags, 1)
+		require.Equal(t, diag.CodeInvalidOutput, diags[0].Code)
+		require.Contains(t, diags[0].Summary, `unknown component "discovery.unknown"`)
+	})
+}
+
+func TestConvertWithSummary(t *testing.T) {
+	in := []byte(`
+scrape_configs:
+  - job_name: web-app
+    static_configs:
+      - targets: ["localhost:9090"]
+  - job_name: mixed
+    kubernetes_sd_configs:
+      - role: pod
+    scaleway_sd_configs:
+      - role: instance
+        project_id: "11111111-1111-1111-1111-111111111111"
+        access_key: SCWXXXXXXXXXXXXXXXXX
+        secret_key: 11111111-1111-1111-1111-111111111111
+  - job_name: web_app
+    static_configs:
+      - targets: ["localhost:9091"]
+remote_write:
+  - url: http://localhost:9009/api/prom/push
+  - url: http://localhost:9010/api/prom/push
+`)
+
+	t.Run("all sections", func(t *testing.T) {
+		_, summary, _ := ConvertWithSummary(in, InputPrometheus, ConvertOptions{})
+		require.Equal(t, map[string]FeatureCount{
+			"scrape_config":     {Converted: 3},
+			"service_discovery": {Converted: 3, Unsupported: 1},
+			"remote_write":      {Converted: 2},
+		}, summary.Categories)
+		require.InDelta(t, 8.0/9.0, summary.Coverage(), 0.0001)
+	})
+
+	t.Run("remote_write only", func(t *testing.T) {
+		_, summary, _ := ConvertWithSummary(in, InputPrometheus, ConvertOptions{Sections: []string{"remote_write"}})
+		require.Equal(t, map[string]FeatureCount{
+			"scrape_config":     {Skipped: 3},
+			"service_discovery": {Skipped: 4},
+			"remote_write":      {Converted: 2},
+		}, summary.Categories)
+		require.Equal(t, 1.0, summary.Coverage())
+	})
+}
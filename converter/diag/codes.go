@@ -0,0 +1,102 @@
+package diag
+
+// Code is a stable identifier for a kind of diagnostic. Unlike a diagnostic's
+// summary, a code never changes between releases, so tooling can reliably key
+// off it, for example to allowlist known unsupported features in CI.
+//
+// Codes are prefixed by the converter which emits them.
+type Code string
+
+// Codes emitted by the converter package itself.
+const (
+	// CodeUnrecognizedKind is emitted when the kind of the input config is not
+	// supported by the converter.
+	CodeUnrecognizedKind Code = "CONV001_UNRECOGNIZED_KIND"
+
+	// CodeUnrecognizedSection is emitted when a requested section of the input
+	// config is not supported by the converter.
+	CodeUnrecognizedSection Code = "CONV002_UNRECOGNIZED_SECTION"
+
+	// CodeSkippedSection is emitted when a section of the input config was not
+	// converted because it wasn't requested.
+	CodeSkippedSection Code = "CONV003_SKIPPED_SECTION"
+
+	// CodeInvalidOutput is emitted when the generated config can't be parsed
+	// or declares invalid components, which indicates a bug in the converter.
+	CodeInvalidOutput Code = "CONV004_INVALID_OUTPUT"
+
+	// CodeInvalidTargetVersion is emitted when the requested target version
+	// can't be parsed.
+	CodeInvalidTargetVersion Code = "CONV005_INVALID_TARGET_VERSION"
+)
+
+// Codes emitted when converting Prometheus configs.
+const (
+	// CodePromUnsupportedSD is emitted for a service discovery config which
+	// has no Flow equivalent.
+	CodePromUnsupportedSD Code = "PROM001_UNSUPPORTED_SD"
+
+	// CodePromDuplicateName is emitted when a job or remote_write name is used
+	// more than once.
+	CodePromDuplicateName Code = "PROM002_DUPLICATE_NAME"
+
+	// CodePromCollidingLabel is emitted when two scrape configs would convert
+	// to components with the same label, and one of them is given a numeric
+	// suffix.
+	CodePromCollidingLabel Code = "PROM003_COLLIDING_LABEL"
+
+	// CodePromParseFailed is emitted when the input can't be parsed as a
+	// Prometheus config.
+	CodePromParseFailed Code = "PROM004_PARSE_FAILED"
+
+	// CodePromRenderFailed is emitted when the converted Flow config can't be
+	// rendered.
+	CodePromRenderFailed Code = "PROM005_RENDER_FAILED"
+
+	// CodePromRewriteFailed is emitted when the input can't be rewritten
+	// after removing fields unknown to the Prometheus config loader.
+	CodePromRewriteFailed Code = "PROM006_REWRITE_FAILED"
+
+	// CodePromInvalidField is emitted for a field whose value is invalid.
+	CodePromInvalidField Code = "PROM007_INVALID_FIELD"
+
+	// CodePromUnsupportedField is emitted for a field whose value can't be
+	// represented in Flow, so the converted config behaves differently.
+	CodePromUnsupportedField Code = "PROM008_UNSUPPORTED_FIELD"
+
+	// CodePromDroppedField is emitted for a field which was dropped because
+	// Flow already behaves as the field requests.
+	CodePromDroppedField Code = "PROM009_DROPPED_FIELD"
+
+	// CodePromTargetLimit is emitted for a scrape config which sets
+	// target_limit, which prometheus.scrape enforces differently.
+	CodePromTargetLimit Code = "PROM010_TARGET_LIMIT"
+
+	// CodePromUnresolvedVariable is emitted for a ${VAR} reference in the
+	// input which couldn't be expanded.
+	CodePromUnresolvedVariable Code = "PROM011_UNRESOLVED_VARIABLE"
+
+	// CodePromRelativePath is emitted for a relative file path, which Flow
+	// resolves differently than Prometheus.
+	CodePromRelativePath Code = "PROM012_RELATIVE_PATH"
+
+	// CodePromRemoteRead is emitted for a remote_read block, which has no Flow
+	// equivalent.
+	CodePromRemoteRead Code = "PROM013_REMOTE_READ"
+
+	// CodePromLogsConfig is emitted for a logs section embedded in the input,
+	// which is out of scope for the Prometheus converter.
+	CodePromLogsConfig Code = "PROM014_LOGS_CONFIG"
+
+	// CodePromMissingFile is emitted when a file included by the input can't
+	// be found or read.
+	CodePromMissingFile Code = "PROM015_MISSING_FILE"
+
+	// CodePromTargetVersion is emitted for a feature of the input which
+	// requires a newer Grafana Agent release than the target version.
+	CodePromTargetVersion Code = "PROM016_TARGET_VERSION"
+
+	// CodePromAlerting is emitted for an alerting block, which has no Flow
+	// equivalent.
+	CodePromAlerting Code = "PROM017_ALERTING"
+)
@@ -0,0 +1,114 @@
+// Package diag exposes the diagnostic types returned by the converter.
+package diag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity denotes the severity level of a diagnostic.
+type Severity int
+
+// Supported severity levels.
+const (
+	// SeverityLevelInfo is used for informational messages which do not
+	// require action from the user.
+	SeverityLevelInfo Severity = iota
+
+	// SeverityLevelWarn is used when the conversion succeeded but the
+	// resulting config may behave differently from the input config.
+	SeverityLevelWarn
+
+	// SeverityLevelError is used when part of the input config could not be
+	// converted.
+	SeverityLevelError
+
+	// SeverityLevelCritical is used when the conversion could not complete at
+	// all.
+	SeverityLevelCritical
+)
+
+// String returns the string representation of s.
+func (s Severity) String() string {
+	switch s {
+	case SeverityLevelInfo:
+		return "info"
+	case SeverityLevelWarn:
+		return "warning"
+	case SeverityLevelError:
+		return "error"
+	case SeverityLevelCritical:
+		return "critical"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+// Diagnostic is an individual diagnostic message emitted while converting a
+// config.
+type Diagnostic struct {
+	// Severity holds the severity level of this Diagnostic.
+	Severity Severity
+
+	// Code is a stable identifier for the kind of this Diagnostic.
+	Code Code
+
+	// Summary is a short, human-readable description of the diagnostic.
+	Summary string
+}
+
+// String returns the string representation of d.
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("(%s) %s", d.Severity, d.Summary)
+}
+
+// Diagnostics is a collection of diagnostic messages.
+type Diagnostics []Diagnostic
+
+// Add adds a new Diagnostic with the given severity, code, and summary to the
+// list of diagnostics.
+func (ds *Diagnostics) Add(severity Severity, code Code, summary string) {
+	*ds = append(*ds, Diagnostic{
+		Severity: severity,
+		Code:     code,
+		Summary:  summary,
+	})
+}
+
+// Error implements error.
+func (ds Diagnostics) Error() string {
+	switch len(ds) {
+	case 0:
+		return "no diagnostics"
+	case 1:
+		return ds[0].String()
+	}
+
+	msgs := make([]string, 0, len(ds))
+	for _, d := range ds {
+		msgs = append(msgs, d.String())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// HasErrors reports whether the list of Diagnostics contain any error-level
+// or critical-level diagnostic.
+func (ds Diagnostics) HasErrors() bool {
+	for _, d := range ds {
+		if d.Severity >= SeverityLevelError {
+			return true
+		}
+	}
+	return false
+}
+
+// HasCritical reports whether the list of Diagnostics contain any
+// critical-level diagnostic.
+func (ds Diagnostics) HasCritical() bool {
+	for _, d := range ds {
+		if d.Severity == SeverityLevelCritical {
+			return true
+		}
+	}
+	return false
+}
@@ -1,18 +1,82 @@
 package common
 
 import (
+	"strings"
+
 	"github.com/grafana/agent/component"
+	"github.com/grafana/agent/component/common/config"
+	"github.com/grafana/agent/component/common/relabel"
 	"github.com/grafana/agent/pkg/river/rivertypes"
+	"github.com/grafana/agent/pkg/river/token"
 	"github.com/grafana/agent/pkg/river/token/builder"
 )
 
 // AppendBlockWithOverride appends Flow component arguments using the convert
 // value override hook to the file we are building.
 func AppendBlockWithOverride(f *builder.File, name []string, label string, args component.Arguments) {
+	f.Body().AppendBlock(NewBlockWithOverride(name, label, args))
+}
+
+// NewBlockWithOverride generates a new [*builder.Block] for Flow component
+// arguments using the convert value override hook. The block may be modified
+// further before it is appended to the file we are building.
+func NewBlockWithOverride(name []string, label string, args component.Arguments) *builder.Block {
 	block := builder.NewBlock(name, label)
 	block.Body().SetValueOverrideHook(getValueOverrideHook())
 	block.Body().AppendFrom(args)
-	f.Body().AppendBlock(block)
+	return block
+}
+
+// SetHTTPClientDisabledDefaults explicitly sets the attributes of
+// httpClientConfig which are disabled but default to enabled in Flow. The
+// builder omits optional fields set to their zero value, so without this a
+// disabled option would silently be enabled again by the generated config.
+func SetHTTPClientDisabledDefaults(body *builder.Body, httpClientConfig config.HTTPClientConfig) {
+	if !httpClientConfig.FollowRedirects {
+		body.SetAttributeValue("follow_redirects", false)
+	}
+	if !httpClientConfig.EnableHTTP2 {
+		body.SetAttributeValue("enable_http2", false)
+	}
+}
+
+// SanitizeIdentifier returns name with every character which is not valid in
+// a River identifier replaced by an underscore, so that name can be used as a
+// block label.
+func SanitizeIdentifier(name string) string {
+	var sb strings.Builder
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+			sb.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				sb.WriteRune('_')
+			}
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune('_')
+		}
+	}
+	if sb.Len() == 0 {
+		return "_"
+	}
+	return sb.String()
+}
+
+// AppendComment appends a comment made of one or more lines to the file we
+// are building. The comment is attached to the next statement appended to the
+// file.
+func AppendComment(f *builder.File, lines ...string) {
+	// Separate the comment from the previous statement with a blank line.
+	toks := []builder.Token{{Tok: token.LITERAL, Lit: "\n"}}
+	for i, line := range lines {
+		if i > 0 {
+			toks = append(toks, builder.Token{Tok: token.LITERAL, Lit: "\n"})
+		}
+		toks = append(toks, builder.Token{Tok: token.COMMENT, Lit: "// " + line})
+	}
+	f.Body().AppendTokens(toks)
 }
 
 // GetValueOverrideHook returns a hook for overriding the go value of
@@ -22,6 +86,19 @@ func getValueOverrideHook() builder.ValueOverrideHook {
 		switch value := val.(type) {
 		case rivertypes.Secret:
 			return string(value)
+		case map[string][]rivertypes.Secret:
+			res := make(map[string][]string, len(value))
+			for name, secrets := range value {
+				values := make([]string, 0, len(secrets))
+				for _, s := range secrets {
+					values = append(values, string(s))
+				}
+				res[name] = values
+			}
+			return res
+		case relabel.Regexp:
+			// Regexps are stored anchored; print the expression as written.
+			return strings.TrimSuffix(strings.TrimPrefix(value.String(), "^(?:"), ")$")
 		default:
 			return val
 		}
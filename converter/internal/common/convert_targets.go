@@ -0,0 +1,45 @@
+package common
+
+import (
+	"github.com/grafana/agent/component/discovery"
+	"github.com/grafana/agent/pkg/river/token"
+	"github.com/grafana/agent/pkg/river/token/builder"
+)
+
+// ConvertTargets holds the set of targets passed to a component, made up of
+// static targets and of expressions referencing the targets exported by
+// other components.
+type ConvertTargets struct {
+	Static []discovery.Target
+	Exprs  []string
+}
+
+// Tokens returns the River tokens for the targets. Multiple sources of
+// targets are combined using the concat standard library function.
+func (ct ConvertTargets) Tokens() []builder.Token {
+	var sources [][]builder.Token
+	for _, expr := range ct.Exprs {
+		sources = append(sources, []builder.Token{{Tok: token.LITERAL, Lit: expr}})
+	}
+	if len(ct.Static) > 0 || len(ct.Exprs) == 0 {
+		static := builder.NewExpr()
+		static.SetValue(ct.Static)
+		sources = append(sources, static.Tokens())
+	}
+
+	if len(sources) == 1 {
+		return sources[0]
+	}
+
+	toks := []builder.Token{
+		{Tok: token.IDENT, Lit: "concat"},
+		{Tok: token.LPAREN},
+	}
+	for i, source := range sources {
+		if i > 0 {
+			toks = append(toks, builder.Token{Tok: token.COMMA})
+		}
+		toks = append(toks, source...)
+	}
+	return append(toks, builder.Token{Tok: token.RPAREN})
+}
@@ -0,0 +1,60 @@
+package prometheusconvert
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/grafana/agent/converter/diag"
+	"github.com/prometheus/common/model"
+	promconfig "github.com/prometheus/prometheus/config"
+	promdiscovery "github.com/prometheus/prometheus/discovery"
+)
+
+// validateAlertingConfig returns a warning for each Alertmanager configured
+// in alertingConfig. Flow doesn't evaluate rules or send alerts, so the
+// alerting block can't be converted; the warnings describe what was found so
+// it can be configured elsewhere, such as in Grafana Mimir's ruler.
+func validateAlertingConfig(alertingConfig promconfig.AlertingConfig) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for i, am := range alertingConfig.AlertmanagerConfigs {
+		var (
+			static []string
+			sds    []string
+		)
+		for _, sdc := range am.ServiceDiscoveryConfigs {
+			if sc, ok := sdc.(promdiscovery.StaticConfig); ok {
+				for _, tg := range sc {
+					for _, target := range tg.Targets {
+						if addr, ok := target[model.AddressLabel]; ok {
+							static = append(static, string(addr))
+						}
+					}
+				}
+				continue
+			}
+			sds = append(sds, sdc.Name())
+		}
+		sort.Strings(sds)
+
+		var found []string
+		if len(static) > 0 {
+			found = append(found, fmt.Sprintf("static targets %s", strings.Join(static, ", ")))
+		}
+		if len(sds) > 0 {
+			found = append(found, fmt.Sprintf("%s service discovery", strings.Join(sds, ", ")))
+		}
+		if len(found) == 0 {
+			found = append(found, "no targets")
+		}
+
+		diags.Add(diag.SeverityLevelWarn, diag.CodePromAlerting, fmt.Sprintf("alerting.alertmanagers[%d] (%s; scheme %s; path_prefix %q; api_version %s) was not converted: Flow does not send alerts to Alertmanager", i, strings.Join(found, "; "), am.Scheme, am.PathPrefix, am.APIVersion))
+	}
+
+	if len(alertingConfig.AlertRelabelConfigs) > 0 {
+		diags.Add(diag.SeverityLevelWarn, diag.CodePromAlerting, fmt.Sprintf("alerting.alert_relabel_configs (%d rules) were not converted: Flow does not send alerts to Alertmanager", len(alertingConfig.AlertRelabelConfigs)))
+	}
+
+	return diags
+}
@@ -0,0 +1,70 @@
+package prometheusconvert
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// scrapeConfigComments returns the comments written above or beside each
+// scrape_config of in, keyed by job name. Only comments attached to the
+// scrape_config itself or its job_name field are returned; comments on
+// nested fields are ignored.
+//
+// If in cannot be parsed as YAML, no comments are returned.
+func scrapeConfigComments(in []byte) map[string][]string {
+	comments := make(map[string][]string)
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(in, &root); err != nil || len(root.Content) == 0 {
+		return comments
+	}
+	scrapeConfigs := mappingValue(root.Content[0], "scrape_configs")
+	if scrapeConfigs == nil || scrapeConfigs.Kind != yaml.SequenceNode {
+		return comments
+	}
+
+	for _, sc := range scrapeConfigs.Content {
+		if sc.Kind != yaml.MappingNode {
+			continue
+		}
+
+		var (
+			jobName string
+			raw     = []string{sc.HeadComment}
+		)
+		if len(sc.Content) > 0 {
+			raw = append(raw, sc.Content[0].HeadComment)
+		}
+		for i := 0; i+1 < len(sc.Content); i += 2 {
+			key, value := sc.Content[i], sc.Content[i+1]
+			if key.Value == "job_name" {
+				jobName = value.Value
+				raw = append(raw, key.LineComment, value.LineComment)
+				break
+			}
+		}
+		if jobName == "" {
+			continue
+		}
+
+		for _, comment := range raw {
+			comments[jobName] = append(comments[jobName], commentLines(comment)...)
+		}
+	}
+
+	return comments
+}
+
+// commentLines splits a YAML comment into its lines, stripping the leading #
+// of each line and omitting blank lines.
+func commentLines(comment string) []string {
+	var lines []string
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "#"))
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
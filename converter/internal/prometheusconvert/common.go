@@ -1,27 +1,99 @@
 package prometheusconvert
 
 import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
 	"github.com/grafana/agent/component/common/config"
+	"github.com/grafana/agent/converter/diag"
 	"github.com/grafana/agent/pkg/river/rivertypes"
 	promconfig "github.com/prometheus/common/config"
 )
 
+// validateHTTPClientConfig returns diagnostics for settings of
+// httpClientConfig which toHttpClientConfig can't convert. where describes
+// the block httpClientConfig belongs to.
+func validateHTTPClientConfig(httpClientConfig *promconfig.HTTPClientConfig, where string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	// The oauth2 block in Flow only supports proxy_url; other proxy settings
+	// for fetching tokens can't be converted.
+	if oauth2 := httpClientConfig.OAuth2; oauth2 != nil {
+		if oauth2.NoProxy != "" || oauth2.ProxyFromEnvironment || len(oauth2.ProxyConnectHeader) > 0 {
+			diags.Add(diag.SeverityLevelWarn, diag.CodePromUnsupportedField, fmt.Sprintf("unsupported oauth2 proxy settings for %s were not converted: only proxy_url is supported", where))
+		}
+	}
+
+	var relative []string
+	for _, file := range httpClientConfigFiles(httpClientConfig) {
+		if file != "" && !filepath.IsAbs(file) {
+			relative = append(relative, file)
+		}
+	}
+	if len(relative) > 0 {
+		diags.Add(diag.SeverityLevelWarn, diag.CodePromRelativePath, fmt.Sprintf("relative file paths for %s are resolved against the agent's working directory rather than the Prometheus config file's directory: %s", where, strings.Join(relative, ", ")))
+	}
+
+	return diags
+}
+
+// httpClientConfigFiles returns the paths of all files referenced by
+// httpClientConfig, including unset paths.
+func httpClientConfigFiles(httpClientConfig *promconfig.HTTPClientConfig) []string {
+	files := []string{httpClientConfig.BearerTokenFile}
+	if httpClientConfig.BasicAuth != nil {
+		files = append(files, httpClientConfig.BasicAuth.PasswordFile)
+	}
+	if httpClientConfig.Authorization != nil {
+		files = append(files, httpClientConfig.Authorization.CredentialsFile)
+	}
+	if httpClientConfig.OAuth2 != nil {
+		files = append(files, httpClientConfig.OAuth2.ClientSecretFile)
+		files = append(files, tlsConfigFiles(&httpClientConfig.OAuth2.TLSConfig)...)
+	}
+	return append(files, tlsConfigFiles(&httpClientConfig.TLSConfig)...)
+}
+
+func tlsConfigFiles(tlsConfig *promconfig.TLSConfig) []string {
+	return []string{tlsConfig.CAFile, tlsConfig.CertFile, tlsConfig.KeyFile}
+}
+
 func toHttpClientConfig(httpClientConfig *promconfig.HTTPClientConfig) *config.HTTPClientConfig {
 	if httpClientConfig == nil {
 		return nil
 	}
 
 	return &config.HTTPClientConfig{
-		BasicAuth:       toBasicAuth(httpClientConfig.BasicAuth),
-		Authorization:   toAuthorization(httpClientConfig.Authorization),
-		OAuth2:          toOAuth2(httpClientConfig.OAuth2),
-		BearerToken:     rivertypes.Secret(httpClientConfig.BearerToken),
-		BearerTokenFile: httpClientConfig.BearerTokenFile,
-		ProxyURL:        config.URL(httpClientConfig.ProxyURL),
-		TLSConfig:       *toTLSConfig(&httpClientConfig.TLSConfig),
-		FollowRedirects: httpClientConfig.FollowRedirects,
-		EnableHTTP2:     httpClientConfig.EnableHTTP2,
+		BasicAuth:            toBasicAuth(httpClientConfig.BasicAuth),
+		Authorization:        toAuthorization(httpClientConfig.Authorization),
+		OAuth2:               toOAuth2(httpClientConfig.OAuth2),
+		BearerToken:          rivertypes.Secret(httpClientConfig.BearerToken),
+		BearerTokenFile:      httpClientConfig.BearerTokenFile,
+		ProxyURL:             config.URL(httpClientConfig.ProxyURL),
+		NoProxy:              httpClientConfig.NoProxy,
+		ProxyFromEnvironment: httpClientConfig.ProxyFromEnvironment,
+		ProxyConnectHeader:   toProxyConnectHeader(httpClientConfig.ProxyConnectHeader),
+		TLSConfig:            *toTLSConfig(&httpClientConfig.TLSConfig),
+		FollowRedirects:      httpClientConfig.FollowRedirects,
+		EnableHTTP2:          httpClientConfig.EnableHTTP2,
+	}
+}
+
+func toProxyConnectHeader(header promconfig.Header) map[string][]rivertypes.Secret {
+	if len(header) == 0 {
+		return nil
+	}
+
+	res := make(map[string][]rivertypes.Secret, len(header))
+	for name, values := range header {
+		secrets := make([]rivertypes.Secret, 0, len(values))
+		for _, v := range values {
+			secrets = append(secrets, rivertypes.Secret(v))
+		}
+		res[name] = secrets
 	}
+	return res
 }
 
 func toBasicAuth(basicAuth *promconfig.BasicAuth) *config.BasicAuth {
@@ -0,0 +1,163 @@
+package prometheusconvert
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/grafana/agent/component/common/config"
+	"github.com/grafana/agent/component/discovery"
+	"github.com/grafana/agent/component/discovery/http"
+	"github.com/grafana/agent/component/discovery/kubernetes"
+	"github.com/grafana/agent/converter/diag"
+	"github.com/grafana/agent/converter/internal/common"
+	"github.com/grafana/agent/pkg/river/token/builder"
+	promconfig "github.com/prometheus/prometheus/config"
+	promdiscovery "github.com/prometheus/prometheus/discovery"
+	promhttp "github.com/prometheus/prometheus/discovery/http"
+	promkubernetes "github.com/prometheus/prometheus/discovery/kubernetes"
+)
+
+// unconvertedSDComponents maps the names of Prometheus service discovery
+// mechanisms the converter doesn't translate yet to the Flow component which
+// implements them.
+var unconvertedSDComponents = map[string]string{
+	"azure":        "discovery.azure",
+	"consul":       "discovery.consul",
+	"digitalocean": "discovery.digitalocean",
+	"dns":          "discovery.dns",
+	"docker":       "discovery.docker",
+	"ec2":          "discovery.ec2",
+	"file":         "discovery.file",
+	"gce":          "discovery.gce",
+	"lightsail":    "discovery.lightsail",
+}
+
+// unsupportedSDMessage describes why the service discovery mechanism name of
+// the scrape_config jobName wasn't converted.
+func unsupportedSDMessage(name, jobName string) string {
+	if component, ok := unconvertedSDComponents[name]; ok {
+		return fmt.Sprintf("unsupported service discovery %s for scrape_config %q was not converted: the converter does not translate %s_sd_configs yet; configure a %s component by hand, or its targets will not be scraped", name, jobName, name, component)
+	}
+	return fmt.Sprintf("unsupported service discovery %s for scrape_config %q was not converted: there is no Flow component for %s discovery, so its targets will not be scraped", name, jobName, name)
+}
+
+// appendServiceDiscoveryConfigs appends a discovery component for each
+// service discovery config of scrapeConfig to f, returning the combined
+// targets of all service discovery configs. Each service discovery config is
+// tallied in count. Service discovery configs whose component isn't available
+// in target are reported as unsupported. The labels of the discovery
+// components are derived from label and reserved in labels.
+func appendServiceDiscoveryConfigs(f *builder.File, scrapeConfig *promconfig.ScrapeConfig, label string, labels componentLabels, target target, count *Count) (common.ConvertTargets, diag.Diagnostics) {
+	var (
+		targets common.ConvertTargets
+		diags   diag.Diagnostics
+	)
+
+	counts := make(map[string]int)
+	for _, sdc := range scrapeConfig.ServiceDiscoveryConfigs {
+		counts[sdc.Name()]++
+	}
+
+	for i, sdc := range scrapeConfig.ServiceDiscoveryConfigs {
+		sdLabel := label
+		if counts[sdc.Name()] > 1 {
+			sdLabel = fmt.Sprintf("%s_%d", label, i)
+		}
+
+		switch sdc := sdc.(type) {
+		case promdiscovery.StaticConfig:
+			targets.Static = append(targets.Static, toStaticTargets(sdc)...)
+		case *promkubernetes.SDConfig:
+			args := toDiscoveryKubernetes(sdc)
+			sdLabel := labels.reserve([]string{"discovery", "kubernetes"}, sdLabel)
+			block := common.NewBlockWithOverride([]string{"discovery", "kubernetes"}, sdLabel, args)
+			common.SetHTTPClientDisabledDefaults(block.Body(), args.HTTPClientConfig)
+			f.Body().AppendBlock(block)
+			targets.Exprs = append(targets.Exprs, "discovery.kubernetes."+sdLabel+".targets")
+		case *promhttp.SDConfig:
+			if !target.supports(featureDiscoveryHTTP) {
+				diags.Add(diag.SeverityLevelWarn, diag.CodePromTargetVersion, fmt.Sprintf("unsupported service discovery http for scrape_config %q was not converted: %s, so its targets will not be scraped", scrapeConfig.JobName, target.requirement(featureDiscoveryHTTP)))
+				count.Unsupported++
+				continue
+			}
+			args, err := toDiscoveryHTTP(sdc)
+			if err != nil {
+				diags.Add(diag.SeverityLevelError, diag.CodePromInvalidField, fmt.Sprintf("invalid http_sd_config url for scrape_config %q was not converted: %s", scrapeConfig.JobName, err))
+				count.Unsupported++
+				continue
+			}
+			diags = append(diags, validateHTTPClientConfig(&sdc.HTTPClientConfig, fmt.Sprintf("http_sd_config of scrape_config %q", scrapeConfig.JobName))...)
+			sdLabel := labels.reserve([]string{"discovery", "http"}, sdLabel)
+			block := common.NewBlockWithOverride([]string{"discovery", "http"}, sdLabel, args)
+			common.SetHTTPClientDisabledDefaults(block.Body(), args.HTTPClientConfig)
+			f.Body().AppendBlock(block)
+			targets.Exprs = append(targets.Exprs, "discovery.http."+sdLabel+".targets")
+		default:
+			diags.Add(diag.SeverityLevelWarn, diag.CodePromUnsupportedSD, unsupportedSDMessage(sdc.Name(), scrapeConfig.JobName))
+			count.Unsupported++
+			continue
+		}
+		count.Converted++
+	}
+
+	return targets, diags
+}
+
+func toStaticTargets(staticConfig promdiscovery.StaticConfig) []discovery.Target {
+	targets := []discovery.Target{}
+
+	for _, group := range staticConfig {
+		for _, labelSet := range group.Targets {
+			target := make(discovery.Target, len(group.Labels)+len(labelSet))
+			for labelName, labelValue := range group.Labels {
+				target[string(labelName)] = string(labelValue)
+			}
+			for labelName, labelValue := range labelSet {
+				target[string(labelName)] = string(labelValue)
+			}
+			targets = append(targets, target)
+		}
+	}
+
+	return targets
+}
+
+func toDiscoveryKubernetes(sdConfig *promkubernetes.SDConfig) *kubernetes.Arguments {
+	selectors := make([]kubernetes.SelectorConfig, 0, len(sdConfig.Selectors))
+	for _, s := range sdConfig.Selectors {
+		selectors = append(selectors, kubernetes.SelectorConfig{
+			Role:  string(s.Role),
+			Label: s.Label,
+			Field: s.Field,
+		})
+	}
+
+	return &kubernetes.Arguments{
+		APIServer:        config.URL(sdConfig.APIServer),
+		Role:             string(sdConfig.Role),
+		KubeConfig:       sdConfig.KubeConfig,
+		HTTPClientConfig: *toHttpClientConfig(&sdConfig.HTTPClientConfig),
+		NamespaceDiscovery: kubernetes.NamespaceDiscovery{
+			IncludeOwnNamespace: sdConfig.NamespaceDiscovery.IncludeOwnNamespace,
+			Names:               sdConfig.NamespaceDiscovery.Names,
+		},
+		Selectors: selectors,
+		AttachMetadata: kubernetes.AttachMetadataConfig{
+			Node: sdConfig.AttachMetadata.Node,
+		},
+	}
+}
+
+func toDiscoveryHTTP(sdConfig *promhttp.SDConfig) (*http.Arguments, error) {
+	u, err := url.Parse(sdConfig.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Arguments{
+		URL:              config.URL{URL: u},
+		RefreshInterval:  time.Duration(sdConfig.RefreshInterval),
+		HTTPClientConfig: *toHttpClientConfig(&sdConfig.HTTPClientConfig),
+	}, nil
+}
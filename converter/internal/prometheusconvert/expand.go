@@ -0,0 +1,64 @@
+package prometheusconvert
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/grafana/agent/converter/diag"
+)
+
+// envReference matches ${VAR} references to variables, along with escaped
+// $${VAR} references which are rewritten to a literal ${VAR}. Bare $VAR and
+// ${1} references are left alone.
+var envReference = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// relabelTemplate matches lines holding the value of a relabel replacement or
+// target_label. These values refer to the regex's capture groups with the same
+// ${name} syntax as variables, so they're never expanded.
+var relabelTemplate = regexp.MustCompile(`^\s*(?:-\s*)?(?:replacement|target_label)\s*:`)
+
+// expandEnv replaces ${VAR} references in in with values from env. If env is
+// nil, values are looked up from the process environment instead. Unresolved
+// references are replaced with an empty string and reported with a warning
+// diagnostic.
+//
+// References in relabel replacement and target_label values are left alone,
+// since they refer to named capture groups. Elsewhere, $${VAR} can be used to
+// write a literal ${VAR}.
+func expandEnv(in []byte, env map[string]string) ([]byte, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	lookup := os.LookupEnv
+	if env != nil {
+		lookup = func(name string) (string, bool) {
+			value, ok := env[name]
+			return value, ok
+		}
+	}
+
+	reported := make(map[string]bool)
+	expand := func(ref []byte) []byte {
+		if bytes.HasPrefix(ref, []byte("$$")) {
+			return ref[1:]
+		}
+
+		name := string(envReference.FindSubmatch(ref)[1])
+		value, ok := lookup(name)
+		if !ok && !reported[name] {
+			reported[name] = true
+			diags.Add(diag.SeverityLevelWarn, diag.CodePromUnresolvedVariable, fmt.Sprintf("unresolved variable ${%s} was replaced with an empty string", name))
+		}
+		return []byte(value)
+	}
+
+	lines := bytes.SplitAfter(in, []byte("\n"))
+	for i, line := range lines {
+		if relabelTemplate.Match(line) {
+			continue
+		}
+		lines[i] = envReference.ReplaceAllFunc(line, expand)
+	}
+	return bytes.Join(lines, nil), diags
+}
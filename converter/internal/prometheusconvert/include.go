@@ -0,0 +1,144 @@
+package prometheusconvert
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/grafana/agent/converter/diag"
+	"gopkg.in/yaml.v3"
+)
+
+// includedFiles maps each scrape_config node merged from scrape_config_files
+// to the file it was read from. Nodes read from a file keep the line numbers
+// of that file, so diagnostics must name the file alongside the line.
+type includedFiles map[*yaml.Node]string
+
+// lineOf describes line of the file node was read from. node is a
+// scrape_config node, or nil for nodes outside of scrape_configs.
+func (f includedFiles) lineOf(node *yaml.Node, line int) string {
+	if file, ok := f[node]; ok {
+		return fmt.Sprintf("line %d of %s", line, file)
+	}
+	return fmt.Sprintf("line %d", line)
+}
+
+// includeScrapeConfigFiles replaces the scrape_config_files field of doc with
+// the scrape configs of the files it references, appending them to the
+// scrape_configs of doc. Relative patterns are resolved against baseDir, and
+// files matched by them are named relative to baseDir in the returned
+// includedFiles. It returns true if doc was modified.
+//
+// scrape_config_files was introduced in a Prometheus release newer than the
+// one vendored by the converter, so the files must be included before the
+// config is loaded.
+func includeScrapeConfigFiles(doc *yaml.Node, baseDir string, diags *diag.Diagnostics) (includedFiles, bool) {
+	files := make(includedFiles)
+
+	var patternsNode *yaml.Node
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		if doc.Content[i].Value != "scrape_config_files" {
+			continue
+		}
+		patternsNode = doc.Content[i+1]
+		doc.Content = append(doc.Content[:i], doc.Content[i+2:]...)
+		break
+	}
+	if patternsNode == nil {
+		return files, false
+	}
+
+	var patterns []string
+	if err := patternsNode.Decode(&patterns); err != nil {
+		diags.Add(diag.SeverityLevelError, diag.CodePromInvalidField, fmt.Sprintf("invalid scrape_config_files: %s", err))
+		return files, true
+	}
+
+	scrapeConfigs := mappingValue(doc, "scrape_configs")
+	if scrapeConfigs == nil {
+		scrapeConfigs = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		doc.Content = append(doc.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "scrape_configs"}, scrapeConfigs)
+	} else if scrapeConfigs.Kind != yaml.SequenceNode {
+		// Leave reporting the invalid scrape_configs to the config loader.
+		return files, true
+	}
+
+	for _, pattern := range patterns {
+		path := pattern
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(baseDir, path)
+		}
+
+		matches, err := filepath.Glob(path)
+		if err != nil {
+			diags.Add(diag.SeverityLevelError, diag.CodePromInvalidField, fmt.Sprintf("invalid scrape_config_files pattern %q: %s", pattern, err))
+			continue
+		}
+		if len(matches) == 0 {
+			if hasGlobMeta(pattern) {
+				diags.Add(diag.SeverityLevelWarn, diag.CodePromMissingFile, fmt.Sprintf("scrape_config_files pattern %q did not match any files", pattern))
+			} else {
+				diags.Add(diag.SeverityLevelError, diag.CodePromMissingFile, fmt.Sprintf("scrape_config_files file %q does not exist", pattern))
+			}
+			continue
+		}
+
+		for _, file := range matches {
+			name := file
+			if !filepath.IsAbs(pattern) {
+				if rel, err := filepath.Rel(baseDir, file); err == nil {
+					name = rel
+				}
+			}
+
+			included, err := readScrapeConfigFile(file)
+			if err != nil {
+				diags.Add(diag.SeverityLevelError, diag.CodePromMissingFile, fmt.Sprintf("failed to include scrape_config_files file %q: %s", name, err))
+				continue
+			}
+			for _, sc := range included {
+				files[sc] = name
+			}
+			scrapeConfigs.Content = append(scrapeConfigs.Content, included...)
+		}
+	}
+
+	return files, true
+}
+
+// readScrapeConfigFile returns the scrape configs defined in the file at
+// path.
+func readScrapeConfigFile(path string) ([]*yaml.Node, error) {
+	bb, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(bb, &root); err != nil {
+		return nil, err
+	}
+	if len(root.Content) == 0 {
+		return nil, nil
+	}
+
+	scrapeConfigs := mappingValue(root.Content[0], "scrape_configs")
+	if scrapeConfigs == nil {
+		return nil, nil
+	}
+	if scrapeConfigs.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("scrape_configs must be a list")
+	}
+	return scrapeConfigs.Content, nil
+}
+
+// hasGlobMeta returns true if pattern contains any glob metacharacters.
+func hasGlobMeta(pattern string) bool {
+	for _, c := range pattern {
+		switch c {
+		case '*', '?', '[', '\\':
+			return true
+		}
+	}
+	return false
+}
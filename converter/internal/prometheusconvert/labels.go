@@ -0,0 +1,26 @@
+package prometheusconvert
+
+import (
+	"fmt"
+	"strings"
+)
+
+// componentLabels holds the IDs of the components emitted by a conversion,
+// such as "discovery.kubernetes.default". Every component label generated by
+// the converter must be reserved through it, so that two components never
+// share an ID.
+type componentLabels map[string]bool
+
+// reserve reserves a label for a component with the given name. label is
+// returned if it's free; otherwise, label is given the lowest numeric suffix
+// starting at 2 which is free.
+func (l componentLabels) reserve(name []string, label string) string {
+	prefix := strings.Join(name, ".") + "."
+
+	unique := label
+	for i := 2; l[prefix+unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", label, i)
+	}
+	l[prefix+unique] = true
+	return unique
+}
@@ -0,0 +1,494 @@
+package prometheusconvert
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/agent/converter/diag"
+	"github.com/grafana/regexp"
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v3"
+)
+
+// newerField describes a config field which was introduced in a Prometheus
+// release newer than the one vendored by the converter. The vendored config
+// loader rejects unknown fields, so these fields are removed from the input
+// before loading and reported through diagnose instead.
+type newerField struct {
+	name     string
+	diagnose func(where string, value *yaml.Node, diags *diag.Diagnostics)
+}
+
+// logsFields are top-level fields holding logs configuration, which appear in
+// configs combining Prometheus with a logging agent such as Promtail. They
+// aren't Prometheus fields, so they're removed like newer fields.
+var logsFields = []newerField{
+	{name: "logs", diagnose: diagnoseLogsConfig("logs")},
+	{name: "clients", diagnose: diagnoseLogsConfig("clients")},
+	{name: "positions", diagnose: diagnoseLogsConfig("positions")},
+}
+
+// newerGlobalFields are newer fields which may appear in the global block.
+var newerGlobalFields = []newerField{
+	{name: "scrape_protocols", diagnose: diagnoseScrapeProtocols},
+	{name: "metric_name_validation_scheme", diagnose: diagnoseMetricNameValidationScheme},
+}
+
+// newerScrapeFields are newer fields which may appear in a scrape_config
+// block.
+var newerScrapeFields = []newerField{
+	{name: "scrape_protocols", diagnose: diagnoseScrapeProtocols},
+	{name: "enable_compression", diagnose: diagnoseEnableCompression},
+	{name: "scrape_classic_histograms", diagnose: diagnoseScrapeClassicHistograms},
+	{name: "native_histogram_bucket_limit", diagnose: diagnoseNativeHistogramBucketLimit},
+	{name: "metric_name_validation_scheme", diagnose: diagnoseMetricNameValidationScheme},
+	{name: "track_timestamps_staleness", diagnose: diagnoseTrackTimestampsStaleness},
+}
+
+// newerRemoteWriteFields are newer fields which may appear in a remote_write
+// block.
+var newerRemoteWriteFields = []newerField{
+	{name: "azuread", diagnose: diagnoseAzureAD},
+}
+
+// preprocess removes fields from in which are unknown to the vendored
+// Prometheus config loader, returning the rewritten input alongside
+// diagnostics describing how each removed field is handled by Flow.
+//
+// preprocess also validates properties of the input which the Prometheus
+// config loader would reject without enough context to fix them, such as
+// duplicate job names. Error diagnostics returned by preprocess mean that the
+// input is invalid and should not be converted.
+//
+// Anchors and aliases are preserved when the input is rewritten, so they're
+// resolved by the Prometheus config loader as usual. A removed field is
+// removed everywhere its enclosing block is aliased.
+//
+// Scrape configs included through scrape_config_files are resolved relative
+// to baseDir and merged into the input.
+//
+// If in cannot be parsed as YAML, it is returned unmodified so the Prometheus
+// config loader can report the error.
+func preprocess(in []byte, baseDir string) ([]byte, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(in, &root); err != nil || len(root.Content) == 0 {
+		return in, nil
+	}
+	doc := root.Content[0]
+	if doc.Kind != yaml.MappingNode {
+		return in, nil
+	}
+
+	files, changed := includeScrapeConfigFiles(doc, baseDir, &diags)
+
+	diags = append(diags, findDuplicateNames(mappingValue(doc, "scrape_configs"), "job_name", "scrape_configs", files)...)
+	diags = append(diags, findDuplicateNames(mappingValue(doc, "remote_write"), "name", "remote_write", files)...)
+	diags = append(diags, findRemoteReads(mappingValue(doc, "remote_read"))...)
+	if durationDiags := findInvalidDurations(doc, nil, files); len(durationDiags) > 0 {
+		diags = append(diags, durationDiags...)
+	} else {
+		diags = append(diags, findTimeoutsExceedingInterval(doc)...)
+	}
+	diags = append(diags, findInvalidRegexes(doc, files)...)
+
+	changed = removeNewerFields(doc, "config", logsFields, &diags) || changed
+	if global := mappingValue(doc, "global"); global != nil {
+		changed = removeNewerFields(global, "global", newerGlobalFields, &diags) || changed
+	}
+	if scrapeConfigs := mappingValue(doc, "scrape_configs"); scrapeConfigs != nil && scrapeConfigs.Kind == yaml.SequenceNode {
+		for _, sc := range scrapeConfigs.Content {
+			where := "scrape_config"
+			if jobName := mappingValue(sc, "job_name"); jobName != nil {
+				where = fmt.Sprintf("scrape_config %q", jobName.Value)
+			}
+			changed = removeNewerFields(sc, where, newerScrapeFields, &diags) || changed
+		}
+	}
+	if remoteWrites := mappingValue(doc, "remote_write"); remoteWrites != nil && remoteWrites.Kind == yaml.SequenceNode {
+		for _, rw := range remoteWrites.Content {
+			where := "remote_write"
+			if name := mappingValue(rw, "name"); name != nil {
+				where = fmt.Sprintf("remote_write %q", name.Value)
+			} else if url := mappingValue(rw, "url"); url != nil {
+				where = fmt.Sprintf("remote_write %q", url.Value)
+			}
+			changed = removeNewerFields(rw, where, newerRemoteWriteFields, &diags) || changed
+		}
+	}
+
+	if !changed {
+		return in, diags
+	}
+
+	out, err := yaml.Marshal(&root)
+	if err != nil {
+		diags.Add(diag.SeverityLevelCritical, diag.CodePromRewriteFailed, fmt.Sprintf("failed to rewrite Prometheus config: %s", err))
+		return nil, diags
+	}
+	return out, diags
+}
+
+// removeNewerFields removes any of fields from the mapping node, invoking the
+// field's diagnose function for each removed field. It returns true if node
+// was modified.
+func removeNewerFields(node *yaml.Node, where string, fields []newerField, diags *diag.Diagnostics) bool {
+	if node.Kind != yaml.MappingNode {
+		return false
+	}
+
+	var changed bool
+	for _, field := range fields {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value != field.name {
+				continue
+			}
+			field.diagnose(where, node.Content[i+1], diags)
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			changed = true
+			break
+		}
+	}
+	return changed
+}
+
+// findDuplicateNames returns an error diagnostic for each value of key which
+// is used by more than one element of the sequence node seq. section names
+// the sequence in diagnostics.
+func findDuplicateNames(seq *yaml.Node, key string, section string, files includedFiles) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if seq == nil || seq.Kind != yaml.SequenceNode {
+		return diags
+	}
+
+	first := make(map[string]string)
+	for _, elem := range seq.Content {
+		name := mappingValue(elem, key)
+		if name == nil || name.Value == "" {
+			continue
+		}
+
+		if firstLine, ok := first[name.Value]; ok {
+			diags.Add(diag.SeverityLevelError, diag.CodePromDuplicateName, fmt.Sprintf("duplicate %s %q in %s at %s and %s", key, name.Value, section, firstLine, files.lineOf(elem, name.Line)))
+			continue
+		}
+		first[name.Value] = files.lineOf(elem, name.Line)
+	}
+
+	return diags
+}
+
+// durationFields are the names of fields in a Prometheus config which hold a
+// duration.
+var durationFields = map[string]bool{
+	"scrape_interval":          true,
+	"scrape_timeout":           true,
+	"evaluation_interval":      true,
+	"refresh_interval":         true,
+	"remote_timeout":           true,
+	"batch_send_deadline":      true,
+	"min_backoff":              true,
+	"max_backoff":              true,
+	"send_interval":            true,
+	"out_of_order_time_window": true,
+}
+
+// findInvalidDurations returns an error diagnostic for each duration field
+// below node whose value isn't a valid Prometheus duration, such as a bare
+// number without a unit. The Prometheus config loader rejects these without
+// naming the field.
+//
+// scrapeConfig is the scrape_config node holding node, if any, which is used
+// to name the file node was included from.
+func findInvalidDurations(node, scrapeConfig *yaml.Node, files includedFiles) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if durationFields[key.Value] && value.Kind == yaml.ScalarNode {
+				if _, err := model.ParseDuration(value.Value); err != nil {
+					diags.Add(diag.SeverityLevelError, diag.CodePromInvalidField, fmt.Sprintf("invalid %s %q at %s: durations must be a number followed by a unit, such as \"15s\", \"1d\", or \"2w\"", key.Value, value.Value, files.lineOf(scrapeConfig, value.Line)))
+				}
+				continue
+			}
+			diags = append(diags, findInvalidDurations(value, scrapeConfig, files)...)
+		}
+	case yaml.SequenceNode, yaml.DocumentNode:
+		for _, elem := range node.Content {
+			if _, ok := files[elem]; ok {
+				diags = append(diags, findInvalidDurations(elem, elem, files)...)
+				continue
+			}
+			diags = append(diags, findInvalidDurations(elem, scrapeConfig, files)...)
+		}
+	}
+
+	return diags
+}
+
+// findInvalidRegexes returns an error diagnostic for each relabel rule in doc
+// whose regex doesn't compile. Prometheus and Flow compile relabel regexes
+// with the same RE2 engine, so a regex which compiles here also compiles in
+// the converted config.
+func findInvalidRegexes(doc *yaml.Node, files includedFiles) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	check := func(rules *yaml.Node, where string, parent *yaml.Node) {
+		if rules == nil || rules.Kind != yaml.SequenceNode {
+			return
+		}
+		for _, rule := range rules.Content {
+			re := mappingValue(rule, "regex")
+			if re == nil || re.Kind != yaml.ScalarNode {
+				continue
+			}
+			// Prometheus anchors relabel regexes on both ends.
+			if _, err := regexp.Compile("^(?:" + re.Value + ")$"); err != nil {
+				diags.Add(diag.SeverityLevelError, diag.CodePromInvalidField, fmt.Sprintf("invalid regex %q in %s at %s: %s", re.Value, where, files.lineOf(parent, re.Line), err))
+			}
+		}
+	}
+
+	if scrapeConfigs := mappingValue(doc, "scrape_configs"); scrapeConfigs != nil && scrapeConfigs.Kind == yaml.SequenceNode {
+		for _, sc := range scrapeConfigs.Content {
+			where := "scrape_config"
+			if jobName := mappingValue(sc, "job_name"); jobName != nil {
+				where = fmt.Sprintf("scrape_config %q", jobName.Value)
+			}
+			check(mappingValue(sc, "relabel_configs"), "relabel_configs of "+where, sc)
+			check(mappingValue(sc, "metric_relabel_configs"), "metric_relabel_configs of "+where, sc)
+		}
+	}
+	if remoteWrites := mappingValue(doc, "remote_write"); remoteWrites != nil && remoteWrites.Kind == yaml.SequenceNode {
+		for _, rw := range remoteWrites.Content {
+			where := "remote_write"
+			if name := mappingValue(rw, "name"); name != nil {
+				where = fmt.Sprintf("remote_write %q", name.Value)
+			}
+			check(mappingValue(rw, "write_relabel_configs"), "write_relabel_configs of "+where, nil)
+		}
+	}
+
+	return diags
+}
+
+// findTimeoutsExceedingInterval returns an error diagnostic for the global
+// block and each scrape_config whose scrape_timeout is greater than its
+// effective scrape_interval. All durations in doc must be valid.
+func findTimeoutsExceedingInterval(doc *yaml.Node) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	// Prometheus defaults, which are applied when the global block omits
+	// the fields.
+	globalInterval := model.Duration(time.Minute)
+	globalInterval, _ = durationValue(mappingValue(doc, "global"), "scrape_interval", globalInterval)
+	if timeout, ok := durationValue(mappingValue(doc, "global"), "scrape_timeout", 0); ok && timeout > globalInterval {
+		diags.Add(diag.SeverityLevelError, diag.CodePromInvalidField, fmt.Sprintf("global scrape_timeout %s is greater than the global scrape_interval %s", timeout, globalInterval))
+	}
+
+	scrapeConfigs := mappingValue(doc, "scrape_configs")
+	if scrapeConfigs == nil || scrapeConfigs.Kind != yaml.SequenceNode {
+		return diags
+	}
+	for _, sc := range scrapeConfigs.Content {
+		timeout, ok := durationValue(sc, "scrape_timeout", 0)
+		if !ok {
+			continue
+		}
+
+		interval, explicit := durationValue(sc, "scrape_interval", globalInterval)
+		if timeout <= interval {
+			continue
+		}
+
+		where := "scrape_config"
+		if jobName := mappingValue(sc, "job_name"); jobName != nil {
+			where = fmt.Sprintf("scrape_config %q", jobName.Value)
+		}
+		source := "its scrape_interval"
+		if !explicit {
+			source = "the global scrape_interval"
+		}
+		diags.Add(diag.SeverityLevelError, diag.CodePromInvalidField, fmt.Sprintf("scrape_timeout %s for %s is greater than %s %s", timeout, where, source, interval))
+	}
+
+	return diags
+}
+
+// durationValue returns the duration held by key in the mapping node. If the
+// key isn't set, def is returned along with false.
+func durationValue(node *yaml.Node, key string, def model.Duration) (model.Duration, bool) {
+	value := mappingValue(node, key)
+	if value == nil {
+		return def, false
+	}
+	d, err := model.ParseDuration(value.Value)
+	if err != nil {
+		return def, false
+	}
+	return d, true
+}
+
+// findRemoteReads returns a warning diagnostic for each element of the
+// remote_read sequence node seq, since Flow can't read from remote storage.
+func findRemoteReads(seq *yaml.Node) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if seq == nil || seq.Kind != yaml.SequenceNode {
+		return diags
+	}
+
+	for _, elem := range seq.Content {
+		where := "remote_read"
+		if name := mappingValue(elem, "name"); name != nil {
+			where = fmt.Sprintf("remote_read %q", name.Value)
+		} else if url := mappingValue(elem, "url"); url != nil {
+			where = fmt.Sprintf("remote_read %q", url.Value)
+		}
+		diags.Add(diag.SeverityLevelWarn, diag.CodePromRemoteRead, fmt.Sprintf("unsupported %s at line %d was not converted: Grafana Agent Flow only writes metrics and can't serve queries from remote storage", where, elem.Line))
+	}
+
+	return diags
+}
+
+// mappingValue returns the value for key in the mapping node, or nil if node
+// is not a mapping or does not contain key.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// diagnoseLogsConfig returns a diagnose function reporting that the logs
+// configuration in the named field is out of scope for the Prometheus
+// converter.
+func diagnoseLogsConfig(name string) func(string, *yaml.Node, *diag.Diagnostics) {
+	return func(_ string, value *yaml.Node, diags *diag.Diagnostics) {
+		diags.Add(diag.SeverityLevelWarn, diag.CodePromLogsConfig, fmt.Sprintf("logs configuration in %s at line %d was not converted: the Prometheus converter only converts metrics configuration, so the logs configuration must be converted to loki components separately", name, value.Line))
+	}
+}
+
+// diagnoseScrapeProtocols reports whether the protocols requested by
+// scrape_protocols can be honored by prometheus.scrape, which always
+// negotiates one of the text-based exposition formats.
+func diagnoseScrapeProtocols(where string, value *yaml.Node, diags *diag.Diagnostics) {
+	var protocols []string
+	if err := value.Decode(&protocols); err != nil {
+		diags.Add(diag.SeverityLevelError, diag.CodePromInvalidField, fmt.Sprintf("invalid scrape_protocols for %s: %s", where, err))
+		return
+	}
+
+	var unknown []string
+	var wantsProtobuf bool
+	for _, p := range protocols {
+		switch p {
+		case "PrometheusProto":
+			wantsProtobuf = true
+		case "OpenMetricsText1.0.0", "OpenMetricsText0.0.1", "PrometheusText0.0.4":
+		default:
+			unknown = append(unknown, p)
+		}
+	}
+
+	switch {
+	case len(unknown) > 0:
+		diags.Add(diag.SeverityLevelError, diag.CodePromInvalidField, fmt.Sprintf("unrecognized scrape_protocols for %s: %s", where, strings.Join(unknown, ", ")))
+	case wantsProtobuf:
+		diags.Add(diag.SeverityLevelWarn, diag.CodePromUnsupportedField, fmt.Sprintf("unsupported scrape_protocols for %s: prometheus.scrape does not negotiate the PrometheusProto format, so native histograms will not be scraped", where))
+	default:
+		diags.Add(diag.SeverityLevelInfo, diag.CodePromDroppedField, fmt.Sprintf("scrape_protocols for %s was dropped: prometheus.scrape always negotiates the text-based formats", where))
+	}
+}
+
+// diagnoseEnableCompression reports whether enable_compression can be
+// honored by prometheus.scrape, which always requests gzip-compressed
+// responses from targets.
+func diagnoseEnableCompression(where string, value *yaml.Node, diags *diag.Diagnostics) {
+	var enabled bool
+	if err := value.Decode(&enabled); err != nil {
+		diags.Add(diag.SeverityLevelError, diag.CodePromInvalidField, fmt.Sprintf("invalid enable_compression for %s: %s", where, err))
+		return
+	}
+
+	if enabled {
+		diags.Add(diag.SeverityLevelInfo, diag.CodePromDroppedField, fmt.Sprintf("enable_compression for %s was dropped: prometheus.scrape always requests compressed responses", where))
+		return
+	}
+	diags.Add(diag.SeverityLevelWarn, diag.CodePromUnsupportedField, fmt.Sprintf("unsupported enable_compression for %s: prometheus.scrape can't disable compression and will request gzip-compressed responses", where))
+}
+
+// diagnoseAzureAD reports that azuread can't be converted, since
+// prometheus.remote_write doesn't support Azure AD authentication. Converting
+// the endpoint without it would silently send unauthenticated requests.
+func diagnoseAzureAD(where string, _ *yaml.Node, diags *diag.Diagnostics) {
+	diags.Add(diag.SeverityLevelError, diag.CodePromUnsupportedField, fmt.Sprintf("unsupported azuread for %s: prometheus.remote_write does not support Azure AD authentication", where))
+}
+
+// diagnoseScrapeClassicHistograms reports how scrape_classic_histograms is
+// handled. prometheus.scrape never ingests native histograms, so the classic
+// form of every histogram is always scraped.
+func diagnoseScrapeClassicHistograms(where string, value *yaml.Node, diags *diag.Diagnostics) {
+	var enabled bool
+	if err := value.Decode(&enabled); err != nil {
+		diags.Add(diag.SeverityLevelError, diag.CodePromInvalidField, fmt.Sprintf("invalid scrape_classic_histograms for %s: %s", where, err))
+		return
+	}
+
+	if enabled {
+		diags.Add(diag.SeverityLevelInfo, diag.CodePromDroppedField, fmt.Sprintf("scrape_classic_histograms for %s was dropped: prometheus.scrape always scrapes classic histograms", where))
+		return
+	}
+	diags.Add(diag.SeverityLevelWarn, diag.CodePromUnsupportedField, fmt.Sprintf("unsupported scrape_classic_histograms for %s: prometheus.scrape does not ingest native histograms, so histograms are scraped in their classic form only", where))
+}
+
+// diagnoseNativeHistogramBucketLimit reports that native_histogram_bucket_limit
+// has no effect, since prometheus.scrape never ingests native histograms.
+func diagnoseNativeHistogramBucketLimit(where string, _ *yaml.Node, diags *diag.Diagnostics) {
+	diags.Add(diag.SeverityLevelWarn, diag.CodePromUnsupportedField, fmt.Sprintf("unsupported native_histogram_bucket_limit for %s: prometheus.scrape does not ingest native histograms", where))
+}
+
+// diagnoseMetricNameValidationScheme reports whether metric_name_validation_scheme
+// can be honored by prometheus.scrape, which always validates metric and
+// label names against the legacy character set.
+func diagnoseMetricNameValidationScheme(where string, value *yaml.Node, diags *diag.Diagnostics) {
+	var scheme string
+	if err := value.Decode(&scheme); err != nil {
+		diags.Add(diag.SeverityLevelError, diag.CodePromInvalidField, fmt.Sprintf("invalid metric_name_validation_scheme for %s: %s", where, err))
+		return
+	}
+
+	switch scheme {
+	case "", "legacy":
+		diags.Add(diag.SeverityLevelInfo, diag.CodePromDroppedField, fmt.Sprintf("metric_name_validation_scheme for %s was dropped: prometheus.scrape always uses the legacy validation scheme", where))
+	case "utf8":
+		diags.Add(diag.SeverityLevelWarn, diag.CodePromUnsupportedField, fmt.Sprintf("unsupported metric_name_validation_scheme for %s: prometheus.scrape only accepts metric and label names valid under the legacy scheme, so scrapes exposing other UTF-8 names will fail", where))
+	default:
+		diags.Add(diag.SeverityLevelError, diag.CodePromInvalidField, fmt.Sprintf("unrecognized metric_name_validation_scheme %q for %s: must be \"legacy\" or \"utf8\"", scheme, where))
+	}
+}
+
+// diagnoseTrackTimestampsStaleness reports whether track_timestamps_staleness
+// can be honored by prometheus.scrape, which never writes staleness markers
+// for samples exposed with explicit timestamps.
+func diagnoseTrackTimestampsStaleness(where string, value *yaml.Node, diags *diag.Diagnostics) {
+	var enabled bool
+	if err := value.Decode(&enabled); err != nil {
+		diags.Add(diag.SeverityLevelError, diag.CodePromInvalidField, fmt.Sprintf("invalid track_timestamps_staleness for %s: %s", where, err))
+		return
+	}
+
+	if !enabled {
+		diags.Add(diag.SeverityLevelInfo, diag.CodePromDroppedField, fmt.Sprintf("track_timestamps_staleness for %s was dropped: prometheus.scrape never tracks staleness of samples with explicit timestamps", where))
+		return
+	}
+	diags.Add(diag.SeverityLevelWarn, diag.CodePromUnsupportedField, fmt.Sprintf("unsupported track_timestamps_staleness for %s: prometheus.scrape does not write staleness markers for samples with explicit timestamps, so those series only go stale after the lookback period", where))
+}
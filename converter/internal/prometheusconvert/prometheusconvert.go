@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/go-kit/log"
+	"github.com/grafana/agent/converter/diag"
 	"github.com/grafana/agent/converter/internal/common"
 	"github.com/grafana/agent/pkg/river/token/builder"
 	promconfig "github.com/prometheus/prometheus/config"
@@ -13,13 +14,73 @@ import (
 	_ "github.com/prometheus/prometheus/discovery/install" // Register Prometheus SDs
 )
 
+// Options configures how a Prometheus config is converted.
+type Options struct {
+	// Annotate adds a comment above each generated component naming the
+	// Prometheus config block it was converted from.
+	Annotate bool
+
+	// PreserveComments copies the comments written above or beside each
+	// scrape_config's job_name to the generated prometheus.scrape component.
+	PreserveComments bool
+
+	// Sections limits the output to the named sections of the Prometheus
+	// config. The supported sections are "scrape", which includes the
+	// discovery and relabeling components feeding each scrape, and
+	// "remote_write". If Sections is empty, all sections are converted.
+	Sections []string
+
+	// ExpandEnv replaces ${VAR} references in the input with the value of VAR
+	// before parsing it. Values are looked up from Env, or from the process
+	// environment if Env is nil.
+	ExpandEnv bool
+
+	// Env holds the variables used by ExpandEnv.
+	Env map[string]string
+
+	// BaseDir is the directory relative paths of files included by the input
+	// are resolved against, such as those of scrape_config_files. If empty,
+	// the current working directory is used.
+	BaseDir string
+
+	// TargetVersion is the Grafana Agent release the output must be valid
+	// for, such as "v0.33.0". If empty, the latest release is targeted.
+	TargetVersion string
+}
+
+// Supported sections for Options.Sections.
+const (
+	sectionScrape      = "scrape"
+	sectionRemoteWrite = "remote_write"
+)
+
+// includedSections returns the set of sections to convert for opts, along
+// with diagnostics for any unrecognized section name.
+func includedSections(opts Options) (map[string]bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	included := map[string]bool{sectionScrape: true, sectionRemoteWrite: true}
+	if len(opts.Sections) == 0 {
+		return included, diags
+	}
+
+	requested := make(map[string]bool, len(opts.Sections))
+	for _, section := range opts.Sections {
+		if !included[section] {
+			diags.Add(diag.SeverityLevelCritical, diag.CodeUnrecognizedSection, fmt.Sprintf("unrecognized section %q: supported sections are %q and %q", section, sectionScrape, sectionRemoteWrite))
+			continue
+		}
+		requested[section] = true
+	}
+	return requested, diags
+}
+
 // Convert implements a Prometheus config converter.
 //
 // TODO...
 // The implementation of this API is a work in progress.
 // Additional components must be implemented:
 //
-//	prometheus.relabel
 //	discovery.azure
 //	discovery.consul
 //	discovery.digitalocean
@@ -28,30 +89,139 @@ import (
 //	discovery.ec2
 //	discovery.file
 //	discovery.gce
-//	discovery.kubernetes
 //	discovery.lightsail
-//	discovery.relabel
-func Convert(in []byte) ([]byte, error) {
+func Convert(in []byte, opts Options) ([]byte, diag.Diagnostics) {
+	out, _, diags := ConvertWithSummary(in, opts)
+	return out, diags
+}
+
+// ConvertWithSummary is like Convert but also returns a Summary tallying
+// which features of the input were converted. The Summary is empty if the
+// input couldn't be converted at all.
+func ConvertWithSummary(in []byte, opts Options) ([]byte, Summary, diag.Diagnostics) {
+	summary := newSummary()
+
+	sections, diags := includedSections(opts)
+	target, targetDiags := parseTarget(opts)
+	diags = append(diags, targetDiags...)
+	if diags.HasCritical() {
+		return nil, summary, diags
+	}
+
+	if opts.ExpandEnv {
+		var expandDiags diag.Diagnostics
+		in, expandDiags = expandEnv(in, opts.Env)
+		diags = append(diags, expandDiags...)
+	}
+
+	var comments map[string][]string
+	if opts.PreserveComments {
+		comments = scrapeConfigComments(in)
+	}
+
+	in, preprocessDiags := preprocess(in, opts.BaseDir)
+	diags = append(diags, preprocessDiags...)
+	if diags.HasErrors() {
+		return nil, summary, diags
+	}
+
 	promConfig, err := promconfig.Load(string(in), false, log.NewNopLogger())
 	if err != nil {
-		return nil, err
+		diags.Add(diag.SeverityLevelCritical, diag.CodePromParseFailed, fmt.Sprintf("failed to parse Prometheus config: %s", err))
+		return nil, summary, diags
 	}
 
+	diags = append(diags, validateAlertingConfig(promConfig.AlertingConfig)...)
+
 	f := builder.NewFile()
 
-	remoteWriteArgs := toRemotewriteArguments(promConfig)
-	common.AppendBlockWithOverride(f, []string{"prometheus", "remote_write"}, "default", remoteWriteArgs)
+	if sections[sectionRemoteWrite] {
+		for _, rw := range promConfig.RemoteWriteConfigs {
+			diags = append(diags, validateHTTPClientConfig(&rw.HTTPClientConfig, remoteWriteName(rw))...)
+		}
+		remoteWriteArgs := toRemotewriteArguments(promConfig)
+		diags = append(diags, gateRemoteWriteArguments(remoteWriteArgs, promConfig.RemoteWriteConfigs, target)...)
+		diags = append(diags, applyStorageConfig(promConfig.StorageConfig, remoteWriteArgs)...)
+		if opts.Annotate {
+			common.AppendComment(f, "from remote_write")
+		}
+		f.Body().AppendBlock(newRemoteWriteBlock(remoteWriteArgs, target))
+		summary[CategoryRemoteWrite].Converted += len(promConfig.RemoteWriteConfigs)
+	} else {
+		summary[CategoryRemoteWrite].Skipped += len(promConfig.RemoteWriteConfigs)
+		diags.Add(diag.SeverityLevelInfo, diag.CodeSkippedSection, fmt.Sprintf("section %q was skipped: %d remote_write endpoints were not converted", sectionRemoteWrite, len(promConfig.RemoteWriteConfigs)))
+	}
+
+	if !sections[sectionScrape] {
+		diags.Add(diag.SeverityLevelInfo, diag.CodeSkippedSection, fmt.Sprintf("section %q was skipped: %d scrape_configs were not converted", sectionScrape, len(promConfig.ScrapeConfigs)))
+		for _, scrapeConfig := range promConfig.ScrapeConfigs {
+			summary[CategoryScrapeConfig].Skipped++
+			summary[CategoryServiceDiscovery].Skipped += len(scrapeConfig.ServiceDiscoveryConfigs)
+		}
+		return render(f, summary, diags)
+	}
+	if !sections[sectionRemoteWrite] && len(promConfig.ScrapeConfigs) > 0 {
+		diags.Add(diag.SeverityLevelWarn, diag.CodeSkippedSection, "prometheus.scrape components forward to prometheus.remote_write.default, which is not part of the converted sections")
+	}
 
 	forwardTo := make([]storage.Appendable, 0)
 	forwardTo = append(forwardTo, common.ConvertAppendable{Expr: "prometheus.remote_write.default.receiver"})
+	labels := componentLabels{"prometheus.remote_write.default": true}
+	jobLabels := make(map[string]string)
 	for _, scrapeConfig := range promConfig.ScrapeConfigs {
-		scrapeArgs := toScrapeArguments(scrapeConfig, forwardTo)
-		common.AppendBlockWithOverride(f, []string{"prometheus", "scrape"}, scrapeArgs.JobName, scrapeArgs)
+		diags = append(diags, validateScrapeConfig(scrapeConfig)...)
+
+		sanitized := common.SanitizeIdentifier(scrapeConfig.JobName)
+		label := labels.reserve([]string{"prometheus", "scrape"}, sanitized)
+		if label != sanitized {
+			diags.Add(diag.SeverityLevelWarn, diag.CodePromCollidingLabel, fmt.Sprintf("scrape_config job names %q and %q both convert to the component label %q; scrape_config %q was converted with the label %q instead", jobLabels[sanitized], scrapeConfig.JobName, sanitized, scrapeConfig.JobName, label))
+		}
+		jobLabels[label] = scrapeConfig.JobName
+
+		targets, sdDiags := appendServiceDiscoveryConfigs(f, scrapeConfig, label, labels, target, summary[CategoryServiceDiscovery])
+		diags = append(diags, sdDiags...)
+		diags = append(diags, validateRelabelActions(scrapeConfig, target)...)
+		targets = appendDiscoveryRelabel(f, scrapeConfig.RelabelConfigs, targets, labels.reserve([]string{"discovery", "relabel"}, label))
+
+		// Metric relabeling applies to scraped samples, so it's converted to a
+		// prometheus.relabel component between the scrape and remote_write
+		// rather than merged into the discovery.relabel of the targets.
+		var metricRelabel *builder.Block
+		scrapeForwardTo := forwardTo
+		if len(scrapeConfig.MetricRelabelConfigs) > 0 {
+			relabelLabel := labels.reserve([]string{"prometheus", "relabel"}, label)
+			metricRelabel = newPrometheusRelabelBlock(scrapeConfig.MetricRelabelConfigs, forwardTo, relabelLabel)
+			scrapeForwardTo = []storage.Appendable{common.ConvertAppendable{Expr: "prometheus.relabel." + relabelLabel + ".receiver"}}
+		}
+
+		scrapeArgs := toScrapeArguments(scrapeConfig, scrapeForwardTo)
+		comment := comments[scrapeConfig.JobName]
+		if opts.Annotate {
+			comment = append(comment, fmt.Sprintf("from scrape_config job_name=%q", scrapeConfig.JobName))
+		}
+		if len(comment) > 0 {
+			common.AppendComment(f, comment...)
+		}
+		block := common.NewBlockWithOverride([]string{"prometheus", "scrape"}, label, scrapeArgs)
+		block.Body().SetAttributeTokens("targets", targets.Tokens())
+		common.SetHTTPClientDisabledDefaults(block.Body(), scrapeArgs.HTTPClientConfig)
+		f.Body().AppendBlock(block)
+		if metricRelabel != nil {
+			f.Body().AppendBlock(metricRelabel)
+		}
+		summary[CategoryScrapeConfig].Converted++
 	}
 
+	return render(f, summary, diags)
+}
+
+// render renders f, appending a critical diagnostic to diags if rendering
+// fails.
+func render(f *builder.File, summary Summary, diags diag.Diagnostics) ([]byte, Summary, diag.Diagnostics) {
 	var buf bytes.Buffer
 	if _, err := f.WriteTo(&buf); err != nil {
-		return nil, fmt.Errorf("failed to render Flow config: %w", err)
+		diags.Add(diag.SeverityLevelCritical, diag.CodePromRenderFailed, fmt.Sprintf("failed to render Flow config: %s", err))
+		return nil, newSummary(), diags
 	}
-	return buf.Bytes(), nil
+	return buf.Bytes(), summary, diags
 }
@@ -2,21 +2,50 @@ package prometheusconvert_test
 
 import (
 	"bytes"
+	"errors"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/go-kit/log"
+	"github.com/grafana/agent/component/common/relabel"
+	"github.com/grafana/agent/converter/diag"
 	"github.com/grafana/agent/converter/internal/prometheusconvert"
+	"github.com/grafana/agent/pkg/river/ast"
+	"github.com/grafana/agent/pkg/river/parser"
+	"github.com/grafana/agent/pkg/river/vm"
+	promconfig "github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/model/labels"
+	prom_relabel "github.com/prometheus/prometheus/model/relabel"
 	"github.com/stretchr/testify/require"
 )
 
 const (
-	promSuffix = ".yaml"
-	flowSuffix = ".river"
+	promSuffix  = ".yaml"
+	flowSuffix  = ".river"
+	diagsSuffix = ".diags"
 )
 
+// testOptions maps subdirectories of testdata to the options used to convert
+// the test cases within them. Test cases directly inside testdata use the
+// default options.
+var testOptions = map[string]prometheusconvert.Options{
+	"annotate": {Annotate: true},
+	"expand_env": {
+		ExpandEnv: true,
+		Env:       map[string]string{"SCRAPE_INTERVAL": "30s", "ENVIRONMENT": "production"},
+	},
+	"preserve_comments":   {PreserveComments: true},
+	"scrape_config_files": {BaseDir: filepath.Join("testdata", "scrape_config_files")},
+	"scrape_only":         {Sections: []string{"scrape"}},
+	"target_v0_29":        {TargetVersion: "v0.29.0"},
+	"target_v0_31":        {TargetVersion: "v0.31.0"},
+	"target_v0_33":        {TargetVersion: "v0.33.2"},
+	"unknown_only":        {Sections: []string{"unknown"}},
+}
+
 func TestConvert(t *testing.T) {
 	filepath.WalkDir("testdata", func(path string, d fs.DirEntry, _ error) error {
 		if d.IsDir() {
@@ -26,17 +55,20 @@ func TestConvert(t *testing.T) {
 		if strings.HasSuffix(path, promSuffix) {
 			inputFile := path
 			expectFile := strings.TrimSuffix(path, promSuffix) + flowSuffix
+			diagsFile := strings.TrimSuffix(path, promSuffix) + diagsSuffix
 
 			inputBytes, err := os.ReadFile(inputFile)
 			require.NoError(t, err)
 			expectBytes, err := os.ReadFile(expectFile)
 			require.NoError(t, err)
+			expectDiags := readExpectedDiags(t, diagsFile)
 
-			caseName := filepath.Base(path)
+			caseName, _ := filepath.Rel("testdata", path)
 			caseName = strings.TrimSuffix(caseName, promSuffix)
+			opts := testOptions[filepath.Base(filepath.Dir(path))]
 
 			t.Run(caseName, func(t *testing.T) {
-				testConverter(t, inputBytes, expectBytes)
+				testConverter(t, inputBytes, expectBytes, expectDiags, opts)
 			})
 		}
 
@@ -44,15 +76,278 @@ func TestConvert(t *testing.T) {
 	})
 }
 
-func testConverter(t *testing.T, input, expect []byte) {
+func TestConvert_DiagnosticCodes(t *testing.T) {
+	input, err := os.ReadFile(filepath.Join("testdata", "scaleway_linode"+promSuffix))
+	require.NoError(t, err)
+
+	_, diags := prometheusconvert.Convert(input, prometheusconvert.Options{})
+	require.NotEmpty(t, diags)
+	for _, d := range diags {
+		require.Equal(t, diag.CodePromUnsupportedSD, d.Code, "unexpected code for diagnostic %s", d)
+	}
+}
+
+func TestConvert_InvalidTargetVersion(t *testing.T) {
+	input, err := os.ReadFile(filepath.Join("testdata", "target_version"+promSuffix))
+	require.NoError(t, err)
+
+	out, diags := prometheusconvert.Convert(input, prometheusconvert.Options{TargetVersion: "latest"})
+	require.Nil(t, out)
+	require.Len(t, diags, 1)
+	require.Equal(t, diag.SeverityLevelCritical, diags[0].Severity)
+	require.Equal(t, diag.CodeInvalidTargetVersion, diags[0].Code)
+}
+
+// TestConvert_RelabelEquivalence ensures that converted relabel rules
+// produce exactly the same labels as the Prometheus rules they were converted
+// from. Mistranslated rules still load, so they silently change which targets
+// are scraped or which labels are written.
+func TestConvert_RelabelEquivalence(t *testing.T) {
+	tt := []struct {
+		name   string // Name of the testdata file to convert.
+		metric bool   // Check metric_relabel_configs instead of relabel_configs.
+		cases  []relabelCase
+	}{
+		{
+			// Hashmod sharding must select the same targets for each replica.
+			name: "hashmod_sharding",
+			cases: []relabelCase{
+				{
+					in:     labels.FromStrings("__meta_kubernetes_namespace", "namespace-0", "__address__", "10.0.0.0:8080"),
+					expect: labels.FromStrings("__meta_kubernetes_namespace", "namespace-0", "__address__", "10.0.0.0:8080", "__tmp_hash", "2"),
+				},
+				{
+					in: labels.FromStrings("__meta_kubernetes_namespace", "namespace-1", "__address__", "10.0.0.1:8080"),
+				},
+				{
+					in:     labels.FromStrings("__meta_kubernetes_namespace", "namespace-3", "__address__", "10.0.0.10:8080"),
+					expect: labels.FromStrings("__meta_kubernetes_namespace", "namespace-3", "__address__", "10.0.0.10:8080", "__tmp_hash", "2"),
+				},
+				{
+					in: labels.FromStrings("__meta_kubernetes_namespace", "namespace-6", "__address__", "10.0.0.6:8080"),
+				},
+			},
+		},
+		{
+			// lowercase and uppercase must join source labels with the same
+			// separator.
+			name: "relabel_case",
+			cases: []relabelCase{
+				{
+					in: labels.FromStrings(
+						"__meta_kubernetes_namespace", "Monitoring",
+						"__meta_kubernetes_pod_name", "Prometheus-0",
+						"__meta_kubernetes_pod_label_team", "Observability",
+					),
+					expect: labels.FromStrings(
+						"__meta_kubernetes_namespace", "Monitoring",
+						"__meta_kubernetes_pod_name", "Prometheus-0",
+						"__meta_kubernetes_pod_label_team", "Observability",
+						"instance_id", "MONITORING/PROMETHEUS-0",
+						"team", "observability",
+					),
+				},
+			},
+		},
+		{
+			// metric_relabel_configs must be applied to scraped metrics rather
+			// than merged into the rules applied to targets.
+			name:   "metric_relabel",
+			metric: true,
+			cases: []relabelCase{
+				{in: labels.FromStrings("__name__", "go_gc_duration_seconds")},
+				{in: labels.FromStrings("__name__", "process_cpu_seconds_total")},
+				{
+					in:     labels.FromStrings("__name__", "go_goroutines"),
+					expect: labels.FromStrings("__name__", "go_goroutines"),
+				},
+				{in: labels.FromStrings("__name__", "http_request_duration_seconds_bucket", "le", "0.005")},
+				{
+					in:     labels.FromStrings("__name__", "http_request_duration_seconds_bucket", "le", "0.1"),
+					expect: labels.FromStrings("__name__", "http_request_duration_seconds_bucket", "le", "0.1"),
+				},
+			},
+		},
+		{
+			// Replacements referencing capture groups and containing characters
+			// River must escape must be converted verbatim.
+			name: "relabel_replacement",
+			cases: []relabelCase{
+				{
+					in: labels.FromStrings(
+						"__address__", "10.0.0.1:8080",
+						"__meta_kubernetes_namespace", "monitoring",
+						"__meta_kubernetes_pod_name", "node-exporter-0",
+						"__meta_kubernetes_pod_container_port_number", "9100",
+					),
+					expect: labels.FromStrings(
+						"__address__", "10.0.0.1:9100",
+						"__meta_kubernetes_namespace", "monitoring",
+						"__meta_kubernetes_pod_name", "node-exporter-0",
+						"__meta_kubernetes_pod_container_port_number", "9100",
+						"instance", "monitoring/node-exporter-0:9100 \"$literal\" \\ {tab}\tmonitoringx",
+					),
+				},
+			},
+		},
+		{
+			// The default regex is omitted from converted rules, so Flow must
+			// apply the same default as Prometheus. An empty regex only matches
+			// empty values.
+			name: "relabel_default_regex",
+			cases: []relabelCase{
+				{
+					in:     labels.FromStrings("__address__", "localhost:9090", "__meta_drop_empty", "set", "__meta_replace", "value"),
+					expect: labels.FromStrings("__address__", "localhost:9090", "__meta_drop_empty", "set", "__meta_replace", "value", "replaced", "value"),
+				},
+				{
+					in: labels.FromStrings("__address__", "localhost:9090", "__meta_replace", "value"),
+				},
+			},
+		},
+		{
+			// An explicitly empty separator or replacement differs from the
+			// defaults Flow applies to omitted fields, so it must be kept.
+			name: "relabel_empty_defaults",
+			cases: []relabelCase{
+				{
+					in: labels.FromStrings(
+						"__meta_kubernetes_namespace", "monitoring",
+						"__meta_kubernetes_pod_name", "web-0",
+						"__meta_kubernetes_pod_label_cleared", "true",
+						"__meta_kubernetes_pod_label_team", "observability",
+						"cleared", "previous",
+					),
+					expect: labels.FromStrings(
+						"__meta_kubernetes_namespace", "monitoring",
+						"__meta_kubernetes_pod_name", "web-0",
+						"__meta_kubernetes_pod_label_cleared", "true",
+						"__meta_kubernetes_pod_label_team", "observability",
+						"pod_id", "monitoringweb-0",
+						"team", "observability",
+					),
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			testRelabelEquivalence(t, tc.name, tc.metric, tc.cases)
+		})
+	}
+}
+
+// relabelCase is a set of labels passed through converted relabel rules,
+// along with the labels expected from them. A nil expect means that the rules
+// drop the labels.
+type relabelCase struct {
+	in, expect labels.Labels
+}
+
+// testRelabelEquivalence converts the testdata file name and checks that the
+// converted rules of its first scrape_config relabel each case like the
+// Prometheus rules do. If metric is true, the metric_relabel_configs are
+// checked instead of the relabel_configs.
+func testRelabelEquivalence(t *testing.T, name string, metric bool, cases []relabelCase) {
 	t.Helper()
 
-	actual, err := prometheusconvert.Convert(input)
+	input, err := os.ReadFile(filepath.Join("testdata", name+promSuffix))
+	require.NoError(t, err)
+
+	out, diags := prometheusconvert.Convert(input, prometheusconvert.Options{})
+	require.False(t, diags.HasErrors(), diags.Error())
 
+	promConfig, err := promconfig.Load(string(input), false, log.NewNopLogger())
 	require.NoError(t, err)
+	scrapeConfig := promConfig.ScrapeConfigs[0]
+
+	// Each kind of rule must be converted to the component which applies it.
+	discoveryRules := convertedRelabelRules(t, out, "discovery.relabel")
+	require.Len(t, discoveryRules, len(scrapeConfig.RelabelConfigs))
+	metricRules := convertedRelabelRules(t, out, "prometheus.relabel")
+	require.Len(t, metricRules, len(scrapeConfig.MetricRelabelConfigs))
+
+	promRules, flowRules := scrapeConfig.RelabelConfigs, discoveryRules
+	if metric {
+		promRules, flowRules = scrapeConfig.MetricRelabelConfigs, metricRules
+	}
+
+	for _, c := range cases {
+		expect, expectKeep := prom_relabel.Process(c.in, promRules...)
+		require.Equal(t, c.expect != nil, expectKeep, "unexpected Prometheus keep decision for %s", c.in)
+		if expectKeep {
+			require.Equal(t, c.expect, expect, "unexpected Prometheus labels for %s", c.in)
+		}
+
+		actual, actualKeep := prom_relabel.Process(c.in, relabel.ComponentToPromRelabelConfigs(flowRules)...)
+		require.Equal(t, expectKeep, actualKeep, "keep decision differs for %s", c.in)
+		if expectKeep {
+			require.Equal(t, expect, actual)
+		}
+	}
+}
+
+// convertedRelabelRules returns the rules of every block named blockName in
+// the converted config out, in order.
+func convertedRelabelRules(t *testing.T, out []byte, blockName string) []*relabel.Config {
+	t.Helper()
+
+	f, err := parser.ParseFile("converted.river", out)
+	require.NoError(t, err)
+
+	var rules []*relabel.Config
+	for _, stmt := range f.Body {
+		block, ok := stmt.(*ast.BlockStmt)
+		if !ok || strings.Join(block.Name, ".") != blockName {
+			continue
+		}
+		for _, stmt := range block.Body {
+			rule, ok := stmt.(*ast.BlockStmt)
+			if !ok || strings.Join(rule.Name, ".") != "rule" {
+				continue
+			}
+			var rc relabel.Config
+			require.NoError(t, vm.New(rule).Evaluate(nil, &rc))
+			rules = append(rules, &rc)
+		}
+	}
+	return rules
+}
+
+func testConverter(t *testing.T, input, expect []byte, expectDiags []string, opts prometheusconvert.Options) {
+	t.Helper()
+
+	actual, diags := prometheusconvert.Convert(input, opts)
+
+	actualDiags := make([]string, 0, len(diags))
+	for _, d := range diags {
+		actualDiags = append(actualDiags, d.String())
+	}
+	require.Equal(t, expectDiags, actualDiags)
 	require.Equal(t, string(normalizeLineEndings(expect)), string(normalizeLineEndings(actual))+"\n")
 }
 
+// readExpectedDiags returns the diagnostics listed one per line in path. A
+// missing file means that no diagnostics are expected.
+func readExpectedDiags(t *testing.T, path string) []string {
+	t.Helper()
+
+	diags := []string{}
+	bb, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return diags
+	}
+	require.NoError(t, err)
+
+	for _, line := range strings.Split(string(normalizeLineEndings(bb)), "\n") {
+		if line != "" {
+			diags = append(diags, line)
+		}
+	}
+	return diags
+}
+
 // Replace '\r\n' with '\n'
 func normalizeLineEndings(data []byte) []byte {
 	normalized := bytes.ReplaceAll(data, []byte{'\r', '\n'}, []byte{'\n'})
@@ -0,0 +1,163 @@
+package prometheusconvert
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grafana/agent/component/common/relabel"
+	disc_relabel "github.com/grafana/agent/component/discovery/relabel"
+	prom_relabel_component "github.com/grafana/agent/component/prometheus/relabel"
+	"github.com/grafana/agent/converter/diag"
+	"github.com/grafana/agent/converter/internal/common"
+	"github.com/grafana/agent/pkg/river/token/builder"
+	"github.com/grafana/regexp"
+	promconfig "github.com/prometheus/prometheus/config"
+	prom_relabel "github.com/prometheus/prometheus/model/relabel"
+	"github.com/prometheus/prometheus/storage"
+)
+
+// appendDiscoveryRelabel appends a discovery.relabel component applying
+// relabelConfigs to targets, returning the targets exported by the new
+// component. If there are no relabelConfigs, targets is returned unmodified
+// and no component is appended.
+func appendDiscoveryRelabel(f *builder.File, relabelConfigs []*prom_relabel.Config, targets common.ConvertTargets, label string) common.ConvertTargets {
+	if len(relabelConfigs) == 0 {
+		return targets
+	}
+
+	if idioms := describeRelabelIdioms(relabelConfigs); len(idioms) > 0 {
+		common.AppendComment(f, idioms...)
+	}
+
+	block := common.NewBlockWithOverride([]string{"discovery", "relabel"}, label, &disc_relabel.Arguments{})
+	block.Body().SetAttributeTokens("targets", targets.Tokens())
+	appendRelabelRules(block.Body(), relabelConfigs)
+	f.Body().AppendBlock(block)
+
+	return common.ConvertTargets{Exprs: []string{"discovery.relabel." + label + ".output"}}
+}
+
+// newPrometheusRelabelBlock returns a prometheus.relabel component applying
+// relabelConfigs to scraped metrics before forwarding them to forwardTo.
+func newPrometheusRelabelBlock(relabelConfigs []*prom_relabel.Config, forwardTo []storage.Appendable, label string) *builder.Block {
+	block := common.NewBlockWithOverride([]string{"prometheus", "relabel"}, label, &prom_relabel_component.Arguments{
+		ForwardTo: forwardTo,
+	})
+	appendRelabelRules(block.Body(), relabelConfigs)
+	return block
+}
+
+// validateRelabelActions returns an error diagnostic for each relabel and
+// metric relabel action of scrapeConfig which isn't available in target.
+func validateRelabelActions(scrapeConfig *promconfig.ScrapeConfig, target target) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if target.supports(featureEqualActions) {
+		return diags
+	}
+
+	check := func(relabelConfigs []*prom_relabel.Config, field string) {
+		for _, rc := range relabelConfigs {
+			if rc.Action == prom_relabel.KeepEqual || rc.Action == prom_relabel.DropEqual {
+				diags.Add(diag.SeverityLevelError, diag.CodePromTargetVersion, fmt.Sprintf("unsupported %s action in %s of scrape_config %q: %s", rc.Action, field, scrapeConfig.JobName, target.requirement(featureEqualActions)))
+			}
+		}
+	}
+	check(scrapeConfig.RelabelConfigs, "relabel_configs")
+	check(scrapeConfig.MetricRelabelConfigs, "metric_relabel_configs")
+	return diags
+}
+
+// appendRelabelRules appends a rule block to body for each of
+// relabelConfigs.
+//
+// Each field of a rule is written whenever it differs from the default Flow
+// applies to an omitted field, rather than the Prometheus default. Flow and
+// Prometheus share defaults, but the builder omits zero values, so an
+// explicitly empty separator or replacement must be written out to avoid
+// Flow's defaults replacing it.
+func appendRelabelRules(body *builder.Body, relabelConfigs []*prom_relabel.Config) {
+	flowDefault := relabel.DefaultRelabelConfig
+
+	for _, rc := range relabelConfigs {
+		rule := builder.NewBlock([]string{"rule"}, "")
+		ruleBody := rule.Body()
+
+		if len(rc.SourceLabels) > 0 {
+			sourceLabels := make([]string, 0, len(rc.SourceLabels))
+			for _, sl := range rc.SourceLabels {
+				sourceLabels = append(sourceLabels, string(sl))
+			}
+			ruleBody.SetAttributeValue("source_labels", sourceLabels)
+		}
+		if rc.Separator != flowDefault.Separator {
+			ruleBody.SetAttributeValue("separator", rc.Separator)
+		}
+		if rc.Regex.Regexp != nil && rc.Regex.String() != prom_relabel.DefaultRelabelConfig.Regex.String() {
+			ruleBody.SetAttributeValue("regex", rc.Regex.String())
+		}
+		if rc.Modulus != flowDefault.Modulus {
+			ruleBody.SetAttributeValue("modulus", rc.Modulus)
+		}
+		if rc.TargetLabel != flowDefault.TargetLabel {
+			ruleBody.SetAttributeValue("target_label", rc.TargetLabel)
+		}
+		if rc.Replacement != flowDefault.Replacement {
+			ruleBody.SetAttributeValue("replacement", rc.Replacement)
+		}
+		if string(rc.Action) != string(flowDefault.Action) {
+			ruleBody.SetAttributeValue("action", string(rc.Action))
+		}
+
+		body.AppendBlock(rule)
+	}
+}
+
+var (
+	// namespaceListRegex matches a regex which is an alternation of literal
+	// Kubernetes namespace names.
+	namespaceListRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\|[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+	// annotationLabelRegex matches the meta label for a Kubernetes annotation.
+	annotationLabelRegex = regexp.MustCompile(`^__meta_kubernetes_(pod|service|endpoints|endpointslice|node|ingress)_annotation_(.+)$`)
+
+	// literalValueRegex matches a regex which only matches a literal value.
+	literalValueRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+// describeRelabelIdioms returns a human-readable description for each
+// relabel rule in relabelConfigs which follows a well-known Kubernetes
+// filtering idiom. Rules which don't follow a known idiom are skipped.
+func describeRelabelIdioms(relabelConfigs []*prom_relabel.Config) []string {
+	var res []string
+
+	for _, rc := range relabelConfigs {
+		if rc.Action != prom_relabel.Keep && rc.Action != prom_relabel.Drop {
+			continue
+		}
+		if len(rc.SourceLabels) != 1 || rc.Regex.Regexp == nil {
+			continue
+		}
+
+		verb := "Keeps only"
+		if rc.Action == prom_relabel.Drop {
+			verb = "Drops"
+		}
+
+		var (
+			sourceLabel = string(rc.SourceLabels[0])
+			regex       = rc.Regex.String()
+		)
+
+		switch {
+		case sourceLabel == "__meta_kubernetes_namespace" && namespaceListRegex.MatchString(regex):
+			namespaces := strings.Split(regex, "|")
+			res = append(res, fmt.Sprintf("%s targets in namespaces: %s.", verb, strings.Join(namespaces, ", ")))
+
+		case annotationLabelRegex.MatchString(sourceLabel) && literalValueRegex.MatchString(regex):
+			m := annotationLabelRegex.FindStringSubmatch(sourceLabel)
+			res = append(res, fmt.Sprintf("%s targets whose %s annotation %s is %q.", verb, m[1], m[2], regex))
+		}
+	}
+
+	return res
+}
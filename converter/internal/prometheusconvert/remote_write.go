@@ -1,9 +1,15 @@
 package prometheusconvert
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/grafana/agent/component/prometheus/remotewrite"
+	"github.com/grafana/agent/converter/diag"
+	"github.com/grafana/agent/converter/internal/common"
+	"github.com/grafana/agent/pkg/river/rivertypes"
+	"github.com/grafana/agent/pkg/river/token/builder"
+	"github.com/prometheus/common/sigv4"
 	promconfig "github.com/prometheus/prometheus/config"
 )
 
@@ -15,6 +21,54 @@ func toRemotewriteArguments(promConfig *promconfig.Config) *remotewrite.Argument
 	}
 }
 
+// newRemoteWriteBlock returns a prometheus.remote_write block for args.
+// Endpoints are encoded individually so that options which are disabled but
+// default to enabled in Flow are set explicitly, unless target doesn't
+// support the option at all.
+func newRemoteWriteBlock(args *remotewrite.Arguments, target target) *builder.Block {
+	block := common.NewBlockWithOverride([]string{"prometheus", "remote_write"}, "default", &remotewrite.Arguments{
+		ExternalLabels: args.ExternalLabels,
+	})
+
+	for _, endpoint := range args.Endpoints {
+		endpointBlock := common.NewBlockWithOverride([]string{"endpoint"}, "", endpoint)
+		if !endpoint.SendExemplars && target.supports(featureSendExemplars) {
+			endpointBlock.Body().SetAttributeValue("send_exemplars", false)
+		}
+		if endpoint.HTTPClientConfig != nil {
+			common.SetHTTPClientDisabledDefaults(endpointBlock.Body(), *endpoint.HTTPClientConfig)
+		}
+		block.Body().AppendBlock(endpointBlock)
+	}
+
+	block.Body().AppendFrom(&remotewrite.Arguments{WALOptions: args.WALOptions})
+	return block
+}
+
+// gateRemoteWriteArguments removes settings of args which aren't available in
+// target, returning an error diagnostic for each removed setting. The
+// endpoints of args must have been converted from remoteWriteConfigs.
+func gateRemoteWriteArguments(args *remotewrite.Arguments, remoteWriteConfigs []*promconfig.RemoteWriteConfig, target target) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for i, endpoint := range args.Endpoints {
+		if endpoint.SendExemplars && !target.supports(featureSendExemplars) {
+			diags.Add(diag.SeverityLevelError, diag.CodePromTargetVersion, fmt.Sprintf("send_exemplars for %s was not converted: %s", remoteWriteName(remoteWriteConfigs[i]), target.requirement(featureSendExemplars)))
+			endpoint.SendExemplars = false
+		}
+		if endpoint.SendNativeHistograms && !target.supports(featureSendNativeHistograms) {
+			diags.Add(diag.SeverityLevelError, diag.CodePromTargetVersion, fmt.Sprintf("send_native_histograms for %s was not converted: %s", remoteWriteName(remoteWriteConfigs[i]), target.requirement(featureSendNativeHistograms)))
+			endpoint.SendNativeHistograms = false
+		}
+		if endpoint.SigV4 != nil && !target.supports(featureRemoteWriteSigV4) {
+			diags.Add(diag.SeverityLevelError, diag.CodePromTargetVersion, fmt.Sprintf("sigv4 for %s was not converted: %s", remoteWriteName(remoteWriteConfigs[i]), target.requirement(featureRemoteWriteSigV4)))
+			endpoint.SigV4 = nil
+		}
+	}
+
+	return diags
+}
+
 func getEndpointOptions(remoteWriteConfigs []*promconfig.RemoteWriteConfig) []*remotewrite.EndpointOptions {
 	endpoints := make([]*remotewrite.EndpointOptions, 0)
 
@@ -29,6 +83,7 @@ func getEndpointOptions(remoteWriteConfigs []*promconfig.RemoteWriteConfig) []*r
 			HTTPClientConfig:     toHttpClientConfig(&remoteWriteConfig.HTTPClientConfig),
 			QueueOptions:         toQueueOptions(&remoteWriteConfig.QueueConfig),
 			MetadataOptions:      toMetadataOptions(&remoteWriteConfig.MetadataConfig),
+			SigV4:                toSigV4(remoteWriteConfig.SigV4Config),
 		}
 
 		endpoints = append(endpoints, endpoint)
@@ -37,6 +92,20 @@ func getEndpointOptions(remoteWriteConfigs []*promconfig.RemoteWriteConfig) []*r
 	return endpoints
 }
 
+func toSigV4(sigv4Config *sigv4.SigV4Config) *remotewrite.SigV4Config {
+	if sigv4Config == nil {
+		return nil
+	}
+
+	return &remotewrite.SigV4Config{
+		Region:    sigv4Config.Region,
+		AccessKey: sigv4Config.AccessKey,
+		SecretKey: rivertypes.Secret(sigv4Config.SecretKey),
+		Profile:   sigv4Config.Profile,
+		RoleARN:   sigv4Config.RoleARN,
+	}
+}
+
 func toQueueOptions(queueConfig *promconfig.QueueConfig) *remotewrite.QueueOptions {
 	return &remotewrite.QueueOptions{
 		Capacity:          queueConfig.Capacity,
@@ -57,3 +126,12 @@ func toMetadataOptions(metadataConfig *promconfig.MetadataConfig) *remotewrite.M
 		MaxSamplesPerSend: metadataConfig.MaxSamplesPerSend,
 	}
 }
+
+// remoteWriteName describes rw in diagnostics by its name, falling back to its
+// URL for unnamed endpoints.
+func remoteWriteName(rw *promconfig.RemoteWriteConfig) string {
+	if rw.Name != "" {
+		return fmt.Sprintf("remote_write %q", rw.Name)
+	}
+	return fmt.Sprintf("remote_write %q", rw.URL.Redacted())
+}
@@ -1,12 +1,12 @@
 package prometheusconvert
 
 import (
+	"fmt"
 	"time"
 
-	"github.com/grafana/agent/component/discovery"
 	"github.com/grafana/agent/component/prometheus/scrape"
+	"github.com/grafana/agent/converter/diag"
 	promconfig "github.com/prometheus/prometheus/config"
-	promdiscovery "github.com/prometheus/prometheus/discovery"
 	"github.com/prometheus/prometheus/storage"
 )
 
@@ -16,7 +16,6 @@ func toScrapeArguments(scrapeConfig *promconfig.ScrapeConfig, forwardTo []storag
 	}
 
 	return &scrape.Arguments{
-		Targets:               getTargets(scrapeConfig),
 		ForwardTo:             forwardTo,
 		JobName:               scrapeConfig.JobName,
 		HonorLabels:           scrapeConfig.HonorLabels,
@@ -38,21 +37,15 @@ func toScrapeArguments(scrapeConfig *promconfig.ScrapeConfig, forwardTo []storag
 	}
 }
 
-func getTargets(scrapeConfig *promconfig.ScrapeConfig) []discovery.Target {
-	targets := []discovery.Target{}
+// validateScrapeConfig returns diagnostics for settings of scrapeConfig which
+// behave in a way users should be made aware of after conversion.
+func validateScrapeConfig(scrapeConfig *promconfig.ScrapeConfig) diag.Diagnostics {
+	var diags diag.Diagnostics
 
-	for _, serviceDiscoveryConfig := range scrapeConfig.ServiceDiscoveryConfigs {
-		switch sdc := serviceDiscoveryConfig.(type) {
-		case promdiscovery.StaticConfig:
-			for _, target := range sdc {
-				for _, labelSet := range target.Targets {
-					for labelName, labelValue := range labelSet {
-						targets = append(targets, map[string]string{string(labelName): string(labelValue)})
-					}
-				}
-			}
-		}
+	if scrapeConfig.TargetLimit > 0 {
+		diags.Add(diag.SeverityLevelInfo, diag.CodePromTargetLimit, fmt.Sprintf("target_limit for scrape_config %q is enforced by prometheus.scrape: if more than %d targets are passed to the component, scrapes for all of its targets fail until the number of targets is back within the limit", scrapeConfig.JobName, scrapeConfig.TargetLimit))
 	}
 
-	return targets
+	diags = append(diags, validateHTTPClientConfig(&scrapeConfig.HTTPClientConfig, fmt.Sprintf("scrape_config %q", scrapeConfig.JobName))...)
+	return diags
 }
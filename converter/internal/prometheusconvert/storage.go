@@ -0,0 +1,41 @@
+package prometheusconvert
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/agent/component/prometheus/remotewrite"
+	"github.com/grafana/agent/converter/diag"
+	promconfig "github.com/prometheus/prometheus/config"
+)
+
+// applyStorageConfig applies the parts of storageConfig which have an
+// equivalent in prometheus.remote_write to remoteWriteArgs, returning
+// diagnostics for the parts which are not carried over.
+//
+// The storage block configures the local TSDB of Prometheus. Flow doesn't
+// run a local TSDB, so most of the block has no equivalent.
+func applyStorageConfig(storageConfig promconfig.StorageConfig, remoteWriteArgs *remotewrite.Arguments) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if tsdb := storageConfig.TSDBConfig; tsdb != nil && tsdb.OutOfOrderTimeWindow > 0 {
+		window := time.Duration(tsdb.OutOfOrderTimeWindowFlag)
+		diags.Add(diag.SeverityLevelWarn, diag.CodePromUnsupportedField, fmt.Sprintf("storage.tsdb.out_of_order_time_window of %s was not converted: the prometheus.remote_write WAL rejects out-of-order samples, configure out-of-order ingestion on the remote_write endpoints instead", window))
+	}
+
+	if exemplars := storageConfig.ExemplarsConfig; exemplars != nil && *exemplars != promconfig.DefaultExemplarsConfig {
+		if exemplars.MaxExemplars > 0 {
+			diags.Add(diag.SeverityLevelInfo, diag.CodePromDroppedField, fmt.Sprintf("storage.exemplars.max_exemplars of %d was dropped: prometheus.remote_write doesn't keep exemplars in memory and sends them from its WAL", exemplars.MaxExemplars))
+			return diags
+		}
+
+		// Exemplar storage is disabled, so Prometheus never sends exemplars
+		// over remote_write.
+		for _, endpoint := range remoteWriteArgs.Endpoints {
+			endpoint.SendExemplars = false
+		}
+		diags.Add(diag.SeverityLevelInfo, diag.CodePromDroppedField, "storage.exemplars.max_exemplars disables exemplar storage, so send_exemplars is disabled for every remote_write endpoint")
+	}
+
+	return diags
+}
@@ -0,0 +1,33 @@
+package prometheusconvert
+
+// Categories of features tallied in a Summary.
+const (
+	CategoryScrapeConfig     = "scrape_config"
+	CategoryServiceDiscovery = "service_discovery"
+	CategoryRemoteWrite      = "remote_write"
+)
+
+// Count tallies the features of a single category.
+type Count struct {
+	// Converted is the number of features which were converted.
+	Converted int
+
+	// Unsupported is the number of features which couldn't be converted.
+	Unsupported int
+
+	// Skipped is the number of features which weren't converted because their
+	// section wasn't requested or their enclosing block wasn't converted.
+	Skipped int
+}
+
+// Summary tallies the features of a converted config by category.
+type Summary map[string]*Count
+
+// newSummary returns a Summary with an empty Count for each category.
+func newSummary() Summary {
+	return Summary{
+		CategoryScrapeConfig:     &Count{},
+		CategoryServiceDiscovery: &Count{},
+		CategoryRemoteWrite:      &Count{},
+	}
+}
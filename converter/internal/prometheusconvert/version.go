@@ -0,0 +1,100 @@
+package prometheusconvert
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/agent/converter/diag"
+)
+
+// version is a Grafana Agent release version.
+type version struct {
+	major, minor, patch int
+}
+
+// parseVersion parses a version of the form v0.33.0. The leading v and the
+// patch number are optional, and pre-release or build suffixes are ignored.
+func parseVersion(s string) (version, error) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexAny(trimmed, "-+"); i >= 0 {
+		trimmed = trimmed[:i]
+	}
+
+	parts := strings.Split(trimmed, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return version{}, fmt.Errorf("expected a version of the form v0.33.0")
+	}
+
+	nums := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return version{}, fmt.Errorf("expected a version of the form v0.33.0")
+		}
+		nums[i] = n
+	}
+	return version{major: nums[0], minor: nums[1], patch: nums[2]}, nil
+}
+
+// less returns true if v is an older release than o.
+func (v version) less(o version) bool {
+	if v.major != o.major {
+		return v.major < o.major
+	}
+	if v.minor != o.minor {
+		return v.minor < o.minor
+	}
+	return v.patch < o.patch
+}
+
+func (v version) String() string {
+	return fmt.Sprintf("v%d.%d.%d", v.major, v.minor, v.patch)
+}
+
+// feature is something the converter may generate which isn't available in
+// every Grafana Agent release.
+type feature struct {
+	name  string
+	since version // First release supporting the feature.
+}
+
+var (
+	featureSendExemplars        = feature{name: "sending exemplars from prometheus.remote_write", since: version{0, 30, 0}}
+	featureSendNativeHistograms = feature{name: "sending native histograms from prometheus.remote_write", since: version{0, 30, 0}}
+	featureEqualActions         = feature{name: "relabeling with keepequal or dropequal", since: version{0, 32, 0}}
+	featureDiscoveryHTTP        = feature{name: "discovery.http", since: version{0, 34, 0}}
+	featureRemoteWriteSigV4     = feature{name: "the sigv4 block of prometheus.remote_write", since: version{0, 34, 0}}
+)
+
+// target is the Grafana Agent release generated configs must be valid for.
+// The zero value targets the latest release, which supports every feature.
+type target struct {
+	version *version
+}
+
+// parseTarget returns the target for opts, along with a critical diagnostic
+// if opts.TargetVersion can't be parsed.
+func parseTarget(opts Options) (target, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if opts.TargetVersion == "" {
+		return target{}, diags
+	}
+
+	v, err := parseVersion(opts.TargetVersion)
+	if err != nil {
+		diags.Add(diag.SeverityLevelCritical, diag.CodeInvalidTargetVersion, fmt.Sprintf("invalid target version %q: %s", opts.TargetVersion, err))
+		return target{}, diags
+	}
+	return target{version: &v}, diags
+}
+
+// supports returns true if f is available in the target release.
+func (t target) supports(f feature) bool {
+	return t.version == nil || !t.version.less(f.since)
+}
+
+// requirement describes why f can't be used for the target release.
+func (t target) requirement(f feature) string {
+	return fmt.Sprintf("%s requires Grafana Agent %s or newer, but the target version is %s", f.name, f.since, t.version)
+}
@@ -0,0 +1,51 @@
+package converter
+
+import "github.com/grafana/agent/converter/internal/prometheusconvert"
+
+// ConversionSummary tallies how much of an input config was converted, by
+// category of feature. For InputPrometheus, the categories are
+// "scrape_config", "service_discovery", and "remote_write".
+type ConversionSummary struct {
+	Categories map[string]FeatureCount
+}
+
+// FeatureCount tallies the features of a single category.
+type FeatureCount struct {
+	// Converted is the number of features which were converted.
+	Converted int
+
+	// Unsupported is the number of features which couldn't be converted.
+	// Diagnostics explain why each of them wasn't converted.
+	Unsupported int
+
+	// Skipped is the number of features which weren't converted because their
+	// section wasn't requested or their enclosing block wasn't converted.
+	Skipped int
+}
+
+// Coverage returns the fraction of features, across all categories, which
+// were converted. Skipped features aren't taken into account. Coverage
+// returns 1 if there are no features to convert.
+func (s ConversionSummary) Coverage() float64 {
+	var converted, total int
+	for _, c := range s.Categories {
+		converted += c.Converted
+		total += c.Converted + c.Unsupported
+	}
+	if total == 0 {
+		return 1
+	}
+	return float64(converted) / float64(total)
+}
+
+func newConversionSummary(summary prometheusconvert.Summary) ConversionSummary {
+	categories := make(map[string]FeatureCount, len(summary))
+	for category, c := range summary {
+		categories[category] = FeatureCount{
+			Converted:   c.Converted,
+			Unsupported: c.Unsupported,
+			Skipped:     c.Skipped,
+		}
+	}
+	return ConversionSummary{Categories: categories}
+}
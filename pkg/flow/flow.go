@@ -53,6 +53,7 @@ import (
 	"time"
 
 	"github.com/go-kit/log/level"
+	"github.com/grafana/agent/component"
 	"github.com/grafana/agent/pkg/cluster"
 	"github.com/grafana/agent/pkg/flow/internal/controller"
 	"github.com/grafana/agent/pkg/flow/internal/dag"
@@ -77,6 +78,19 @@ type Options struct {
 	// created if this is nil.
 	LogSink *logging.Sink
 
+	// ComponentLogLevels overrides the log level of individual components,
+	// keyed by the global ID of the component (e.g.,
+	// "loki.source.api.default", or "module.file.example/loki.source.api.default"
+	// for a component running in a module). Components not present in the map
+	// log at the level configured for LogSink. Modules inherit the overrides of
+	// their parent controller.
+	ComponentLogLevels map[string]logging.Level
+
+	// FeatureFlags enables experimental component behaviors. See
+	// component.FeatureFlags for the lifecycle of flags. Modules inherit the
+	// feature flags of their parent controller.
+	FeatureFlags component.FeatureFlags
+
 	// Tracer for components to use. A no-op tracer will be created if this is
 	// nil.
 	Tracer *tracing.Tracer
@@ -166,6 +180,8 @@ func New(o Options) *Flow {
 		loader = controller.NewLoader(controller.ComponentGlobals{
 			LogSink:       o.LogSink,
 			Logger:        log,
+			LogLevels:     o.ComponentLogLevels,
+			FeatureFlags:  o.FeatureFlags,
 			TraceProvider: tracer,
 			Clusterer:     clusterer,
 			DataPath:      o.DataPath,
@@ -318,6 +334,10 @@ func newFromNode(cn *controller.ComponentNode, edges []dag.Edge) *ComponentInfo
 			UpdatedTime: h.UpdateTime,
 		},
 	}
+	if start := cn.StartTime(); !start.IsZero() {
+		ci.StartTime = &start
+		ci.Uptime = cn.Uptime().String()
+	}
 	return ci
 }
 
@@ -335,8 +355,11 @@ type ComponentInfo struct {
 	References   []string         `json:"referencesTo"`
 	ReferencedBy []string         `json:"referencedBy"`
 	Health       *ComponentHealth `json:"health"`
+	StartTime    *time.Time       `json:"startTime,omitempty"` // Nil if the component isn't running.
+	Uptime       string           `json:"uptime,omitempty"`    // Time since StartTime.
 	Original     string           `json:"original"`
 	Arguments    json.RawMessage  `json:"arguments,omitempty"`
+	Config       string           `json:"config,omitempty"`
 	Exports      json.RawMessage  `json:"exports,omitempty"`
 	DebugInfo    json.RawMessage  `json:"debugInfo,omitempty"`
 }
@@ -10,6 +10,7 @@ import (
 
 	"github.com/grafana/agent/pkg/river/encoding"
 
+	"github.com/go-kit/log/level"
 	"github.com/gorilla/mux"
 	"github.com/grafana/agent/pkg/flow/internal/controller"
 )
@@ -69,6 +70,15 @@ func (f *Flow) ComponentJSON(w io.Writer, ci *ComponentInfo) error {
 	}
 	ci.Arguments = args
 
+	// The rendered config is only informational, so a component whose config
+	// can't be rendered is still reported without it.
+	config, err := foundComponent.Config()
+	if err != nil {
+		level.Warn(f.log).Log("msg", "failed to render component config", "component", ci.ID, "err", err)
+	} else {
+		ci.Config = string(config)
+	}
+
 	exports, err := encoding.ConvertRiverBodyToJSON(foundComponent.Exports())
 	if err != nil {
 		return err
@@ -1,8 +1,12 @@
 package flow
 
 import (
+	"bytes"
+	"context"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/grafana/agent/component"
 	"github.com/grafana/agent/pkg/cluster"
@@ -10,6 +14,7 @@ import (
 	"github.com/grafana/agent/pkg/flow/internal/dag"
 	"github.com/grafana/agent/pkg/flow/internal/testcomponents"
 	"github.com/grafana/agent/pkg/flow/logging"
+	"github.com/grafana/agent/pkg/river"
 	"github.com/stretchr/testify/require"
 )
 
@@ -50,6 +55,225 @@ func TestController_LoadFile_Evaluation(t *testing.T) {
 	require.Equal(t, "hello, world!", out.(testcomponents.PassthroughExports).Output)
 }
 
+func TestController_FeatureFlags(t *testing.T) {
+	tt := []struct {
+		name   string
+		flags  component.FeatureFlags
+		expect string
+	}{
+		{name: "no flags", flags: nil, expect: "hello, world!"},
+		{name: "other flag", flags: component.FeatureFlags{"testcomponents.other": true}, expect: "hello, world!"},
+		{name: "flag disabled", flags: component.FeatureFlags{testcomponents.PassthroughUppercaseFlag: false}, expect: "hello, world!"},
+		{name: "flag enabled", flags: component.FeatureFlags{testcomponents.PassthroughUppercaseFlag: true}, expect: "HELLO, WORLD!"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := testOptions(t)
+			opts.FeatureFlags = tc.flags
+			ctrl := New(opts)
+
+			f, err := ReadFile(t.Name(), []byte(testFile))
+			require.NoError(t, err)
+			require.NoError(t, ctrl.LoadFile(f, nil))
+
+			_, out := getFields(t, ctrl.loader.Graph(), "testcomponents.passthrough.static")
+			require.Equal(t, tc.expect, out.(testcomponents.PassthroughExports).Output)
+		})
+	}
+}
+
+func TestController_ComponentLogLevels(t *testing.T) {
+	tt := []struct {
+		name         string
+		controllerID string
+		levels       map[string]logging.Level
+		expect       bool
+	}{
+		{name: "no overrides", levels: nil, expect: false},
+		{name: "override", levels: map[string]logging.Level{"testcomponents.passthrough.static": logging.LevelInfo}, expect: true},
+		{name: "override in module", controllerID: "module.string.example", levels: map[string]logging.Level{"module.string.example/testcomponents.passthrough.static": logging.LevelInfo}, expect: true},
+		{name: "local ID in module", controllerID: "module.string.example", levels: map[string]logging.Level{"testcomponents.passthrough.static": logging.LevelInfo}, expect: false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			s, err := logging.WriterSink(&buf, logging.SinkOptions{Level: logging.LevelError, Format: logging.FormatLogfmt})
+			require.NoError(t, err)
+
+			opts := testOptions(t)
+			opts.LogSink = s
+			opts.ControllerID = tc.controllerID
+			opts.ComponentLogLevels = tc.levels
+			ctrl := New(opts)
+
+			// The logging block is applied to the sink when the file is loaded.
+			f, err := ReadFile(t.Name(), []byte(`logging { level = "error" }`+testFile))
+			require.NoError(t, err)
+			require.NoError(t, ctrl.LoadFile(f, nil))
+
+			require.Equal(t, tc.expect, strings.Contains(buf.String(), "component=testcomponents.passthrough.static"), buf.String())
+			require.NotContains(t, buf.String(), "component=testcomponents.passthrough.forwarded")
+		})
+	}
+}
+
+func TestController_ComponentJSON_Config(t *testing.T) {
+	ctrl := New(testOptions(t))
+
+	f, err := ReadFile(t.Name(), []byte(testFile))
+	require.NoError(t, err)
+	require.NoError(t, ctrl.LoadFile(f, nil))
+
+	var buf bytes.Buffer
+	ci := &ComponentInfo{ID: "testcomponents.passthrough.static"}
+	require.NoError(t, ctrl.ComponentJSON(&buf, ci))
+	require.Equal(t, `input = "hello, world!"`, ci.Config)
+
+	// Arguments without secrets or capsules round-trip through the rendered config.
+	var args testcomponents.PassthroughConfig
+	require.NoError(t, river.Unmarshal([]byte(ci.Config), &args))
+	in, _ := getFields(t, ctrl.loader.Graph(), "testcomponents.passthrough.static")
+	require.Equal(t, in, args)
+}
+
+func TestController_WaitReady(t *testing.T) {
+	const delay = 500 * time.Millisecond
+
+	ctrl := New(testOptions(t))
+
+	f, err := ReadFile(t.Name(), []byte(`
+		testcomponents.slow_ready "upstream" {
+			delay = "500ms"
+			input = "hello, world!"
+		}
+
+		testcomponents.passthrough "downstream" {
+			input = testcomponents.slow_ready.upstream.output
+		}
+	`))
+	require.NoError(t, err)
+	require.NoError(t, ctrl.LoadFile(f, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	start := time.Now()
+	go ctrl.Run(ctx)
+
+	downstream := ctrl.loader.Graph().GetByID("testcomponents.passthrough.downstream").(*controller.ComponentNode)
+
+	// The dependent must wait for its dependency before starting.
+	require.Eventually(t, func() bool {
+		return strings.HasPrefix(downstream.CurrentHealth().Message, "waiting for testcomponents.slow_ready.upstream")
+	}, delay, 10*time.Millisecond)
+
+	require.Eventually(t, func() bool {
+		return downstream.CurrentHealth().Health == component.HealthTypeHealthy
+	}, 5*time.Second, 10*time.Millisecond)
+	require.GreaterOrEqual(t, time.Since(start), delay)
+}
+
+func TestController_ComponentUptime(t *testing.T) {
+	ctrl := New(testOptions(t))
+
+	f, err := ReadFile(t.Name(), []byte(testFile))
+	require.NoError(t, err)
+	require.NoError(t, ctrl.LoadFile(f, nil))
+
+	node := ctrl.loader.Graph().GetByID("testcomponents.passthrough.static").(*controller.ComponentNode)
+	require.True(t, node.StartTime().IsZero(), "component must not report a start time before running")
+	require.Zero(t, node.Uptime())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go ctrl.Run(ctx)
+
+	require.Eventually(t, func() bool {
+		return !node.StartTime().IsZero()
+	}, 5*time.Second, 10*time.Millisecond)
+
+	first := node.Uptime()
+	time.Sleep(50 * time.Millisecond)
+	require.Greater(t, node.Uptime(), first)
+
+	for _, ci := range ctrl.ComponentInfos() {
+		if ci.ID == "testcomponents.passthrough.static" {
+			require.NotNil(t, ci.StartTime)
+			require.Equal(t, node.StartTime(), *ci.StartTime)
+			require.NotEmpty(t, ci.Uptime)
+		}
+	}
+}
+
+func TestController_DrainPendingWork(t *testing.T) {
+	var buf bytes.Buffer
+	s, err := logging.WriterSink(&buf, logging.SinkOptions{Level: logging.LevelInfo, Format: logging.FormatLogfmt})
+	require.NoError(t, err)
+
+	opts := testOptions(t)
+	opts.LogSink = s
+	ctrl := New(opts)
+
+	f, err := ReadFile(t.Name(), []byte(`
+		testcomponents.pending "partial" {
+			items = 5
+			drain = 3
+		}
+
+		testcomponents.pending "full" {
+			items = 4
+			drain = 10
+		}
+
+		testcomponents.pending "empty" {
+			items = 0
+		}
+	`))
+	require.NoError(t, err)
+	require.NoError(t, ctrl.LoadFile(f, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		ctrl.Run(ctx)
+	}()
+
+	graph := ctrl.loader.Graph()
+	nodes := map[string]*controller.ComponentNode{}
+	for _, id := range []string{"partial", "full", "empty"} {
+		nodes[id] = graph.GetByID("testcomponents.pending." + id).(*controller.ComponentNode)
+	}
+	require.Eventually(t, func() bool {
+		for _, node := range nodes {
+			if node.CurrentHealth().Health != component.HealthTypeHealthy {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NotContains(t, buf.String(), "drained pending work", "components must not report draining before they're stopped")
+
+	cancel()
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "controller did not exit")
+	}
+
+	expect := map[string]string{
+		"partial": "pending=5 drained=3 remaining=2",
+		"full":    "pending=4 drained=4 remaining=0",
+		"empty":   "pending=0 drained=0 remaining=0",
+	}
+	for id, counts := range expect {
+		require.Contains(t, buf.String(), `component=testcomponents.pending.`+id+` level=info msg="drained pending work" `+counts)
+	}
+}
+
 func getFields(t *testing.T, g *dag.Graph, nodeID string) (component.Arguments, component.Exports) {
 	t.Helper()
 
@@ -65,6 +65,7 @@ type DialFunc func(ctx context.Context, network, address string) (net.Conn, erro
 type ComponentGlobals struct {
 	LogSink           *logging.Sink                // Sink used for Logging.
 	Logger            *logging.Logger              // Logger shared between all managed components.
+	LogLevels         map[string]logging.Level     // Per-component log level overrides, keyed by global ID.
 	TraceProvider     trace.TracerProvider         // Tracer shared between all managed components.
 	Clusterer         *cluster.Clusterer           // Clusterer shared between all managed components.
 	DataPath          string                       // Shared directory where component data may be stored
@@ -75,6 +76,7 @@ type ComponentGlobals struct {
 	HTTPListenAddr    string                       // Base address for server
 	DialFunc          DialFunc                     // Function to connect to HTTPListenAddr.
 	ControllerID      string                       // ID of controller.
+	FeatureFlags      component.FeatureFlags       // Experimental behaviors enabled for components.
 }
 
 // ComponentNode is a controller node which manages a user-defined component.
@@ -99,6 +101,10 @@ type ComponentNode struct {
 	managed component.Component // Inner managed component
 	args    component.Arguments // Evaluated arguments for the managed component
 
+	// Components this component depends on directly or indirectly. Set by
+	// the Loader after each Apply.
+	dependencies []*ComponentNode
+
 	doingEval atomic.Bool
 
 	// NOTE(rfratto): health and exports have their own mutex because they may be
@@ -108,6 +114,7 @@ type ComponentNode struct {
 	healthMut  sync.RWMutex
 	evalHealth component.Health // Health of the last evaluate
 	runHealth  component.Health // Health of running the component
+	startTime  time.Time        // Time the managed component last started running
 
 	exportsMut sync.RWMutex
 	exports    component.Exports // Evaluated exports for the managed component
@@ -178,11 +185,16 @@ func getManagedOptions(globals ComponentGlobals, cn *ComponentNode) component.Op
 		globalID = path.Join(globals.ControllerID, cn.nodeID)
 	}
 
+	loggerOpts := []logging.LoggerOption{logging.WithComponentID(cn.nodeID)}
+	if lvl, ok := globals.LogLevels[globalID]; ok {
+		loggerOpts = append(loggerOpts, logging.WithLevel(lvl))
+	}
+
 	wrapped := newWrappedRegisterer()
 	cn.register = wrapped
 	return component.Options{
 		ID:     globalID,
-		Logger: logging.New(logging.LoggerSink(globals.Logger), logging.WithComponentID(cn.nodeID)),
+		Logger: logging.New(logging.LoggerSink(globals.Logger), loggerOpts...),
 		Registerer: prometheus.WrapRegistererWith(prometheus.Labels{
 			"component_id": globalID,
 		}, wrapped),
@@ -193,6 +205,8 @@ func getManagedOptions(globals ComponentGlobals, cn *ComponentNode) component.Op
 		HTTPListenAddr: globals.HTTPListenAddr,
 		DialFunc:       globals.DialFunc,
 		HTTPPath:       path.Join(prefix, cn.nodeID) + "/",
+		FeatureFlags:   globals.FeatureFlags,
+		LogLevels:      globals.LogLevels,
 
 		OnStateChange: cn.setExports,
 	}
@@ -305,6 +319,10 @@ func (cn *ComponentNode) evaluate(scope *vm.Scope) error {
 
 	if cn.managed == nil {
 		// We haven't built the managed component successfully yet.
+		if err := component.CheckRequirements(cn.reg, cn.managedOpts); err != nil {
+			return fmt.Errorf("building component: %w", err)
+		}
+
 		managed, err := cn.reg.Build(cn.managedOpts, argsCopyValue)
 		if err != nil {
 			return fmt.Errorf("building component: %w", err)
@@ -335,19 +353,36 @@ func (cn *ComponentNode) evaluate(scope *vm.Scope) error {
 // canceled. Evaluate must have been called at least once without retuning an
 // error before calling Run.
 //
+// Before running the managed component, Run waits for the components it
+// depends on to become ready. See component.ReadyComponent.
+//
 // Run will immediately return ErrUnevaluated if Evaluate has never been called
 // successfully. Otherwise, Run will return nil.
 func (cn *ComponentNode) Run(ctx context.Context) error {
 	cn.mut.RLock()
 	managed := cn.managed
+	dependencies := cn.dependencies
 	cn.mut.RUnlock()
 
 	if managed == nil {
 		return ErrUnevaluated
 	}
 
+	cn.waitDependencies(ctx, dependencies)
+	if ctx.Err() != nil {
+		cn.setRunHealth(component.HealthTypeExited, "component shut down before starting")
+		return nil
+	}
+
 	cn.setRunHealth(component.HealthTypeHealthy, "started component")
-	err := cn.managed.Run(ctx)
+	cn.setStartTime(time.Now())
+	var err error
+	if dc, ok := managed.(component.DrainComponent); ok {
+		err = cn.runDraining(ctx, dc)
+	} else {
+		err = managed.Run(ctx)
+	}
+	cn.setStartTime(time.Time{})
 
 	var exitMsg string
 	logger := cn.managedOpts.Logger
@@ -363,6 +398,95 @@ func (cn *ComponentNode) Run(ctx context.Context) error {
 	return err
 }
 
+// runDraining runs dc until ctx is canceled. dc is given its own context so
+// its pending work can be recorded before it observes the stop, and the
+// amount of that work it drained is logged once it exits.
+func (cn *ComponentNode) runDraining(ctx context.Context, dc component.DrainComponent) error {
+	runCtx, cancel := context.WithCancel(detachedContext{ctx})
+	defer cancel()
+
+	var (
+		pending  = -1
+		stopping = make(chan struct{})
+		exited   = make(chan struct{})
+	)
+	go func() {
+		defer close(stopping)
+		select {
+		case <-ctx.Done():
+			pending = dc.PendingWork()
+			cancel()
+		case <-exited:
+		}
+	}()
+
+	err := dc.Run(runCtx)
+	close(exited)
+	<-stopping
+
+	if pending < 0 {
+		// The component exited on its own rather than being stopped.
+		return err
+	}
+
+	remaining := dc.PendingWork()
+	drained := pending - remaining
+	if drained < 0 {
+		drained = 0
+	}
+
+	level.Info(cn.managedOpts.Logger).Log("msg", "drained pending work", "pending", pending, "drained", drained, "remaining", remaining)
+	return err
+}
+
+// detachedContext is a context.Context which carries the values of its parent
+// but is never canceled with it.
+type detachedContext struct{ parent context.Context }
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+func (c detachedContext) Value(key any) any         { return c.parent.Value(key) }
+
+// waitDependencies waits for each component in dependencies which implements
+// component.ReadyComponent to become ready. Dependencies which fail to become
+// ready are logged and otherwise ignored.
+func (cn *ComponentNode) waitDependencies(ctx context.Context, dependencies []*ComponentNode) {
+	logger := cn.managedOpts.Logger
+
+	for _, dep := range dependencies {
+		rc, ok := dep.readyComponent()
+		if !ok {
+			continue
+		}
+
+		cn.setRunHealth(component.HealthTypeUnknown, fmt.Sprintf("waiting for %s to be ready", dep.NodeID()))
+		if err := rc.WaitReady(ctx); err != nil && ctx.Err() == nil {
+			level.Warn(logger).Log("msg", "dependency failed to become ready; starting anyway", "dependency", dep.NodeID(), "err", err)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// readyComponent returns the managed component if it implements
+// component.ReadyComponent.
+func (cn *ComponentNode) readyComponent() (component.ReadyComponent, bool) {
+	cn.mut.RLock()
+	defer cn.mut.RUnlock()
+
+	rc, ok := cn.managed.(component.ReadyComponent)
+	return rc, ok
+}
+
+// setDependencies sets the components which cn waits on before running.
+func (cn *ComponentNode) setDependencies(dependencies []*ComponentNode) {
+	cn.mut.Lock()
+	defer cn.mut.Unlock()
+	cn.dependencies = dependencies
+}
+
 // ErrUnevaluated is returned if ComponentNode.Run is called before a managed
 // component is built.
 var ErrUnevaluated = errors.New("managed component not built")
@@ -455,6 +579,31 @@ func (cn *ComponentNode) CurrentHealth() component.Health {
 	return component.LeastHealthy(runHealth, evalHealth)
 }
 
+// StartTime returns the time the managed component last started running. The
+// zero time is returned if the managed component isn't currently running.
+func (cn *ComponentNode) StartTime() time.Time {
+	cn.healthMut.RLock()
+	defer cn.healthMut.RUnlock()
+	return cn.startTime
+}
+
+// Uptime returns how long the managed component has been running since it
+// last started, or 0 if it isn't currently running.
+func (cn *ComponentNode) Uptime() time.Duration {
+	start := cn.StartTime()
+	if start.IsZero() {
+		return 0
+	}
+	return time.Since(start)
+}
+
+// setStartTime sets the time the managed component last started running.
+func (cn *ComponentNode) setStartTime(t time.Time) {
+	cn.healthMut.Lock()
+	defer cn.healthMut.Unlock()
+	cn.startTime = t
+}
+
 // DebugInfo returns debugging information from the managed component (if any).
 func (cn *ComponentNode) DebugInfo() interface{} {
 	cn.mut.RLock()
@@ -466,6 +615,20 @@ func (cn *ComponentNode) DebugInfo() interface{} {
 	return nil
 }
 
+// Config returns the current configuration of the managed component rendered
+// as River. If the managed component doesn't implement
+// component.ConfigComponent, its most recent Arguments are rendered instead.
+func (cn *ComponentNode) Config() ([]byte, error) {
+	cn.mut.RLock()
+	cc, ok := cn.managed.(component.ConfigComponent)
+	cn.mut.RUnlock()
+
+	if ok {
+		return component.MarshalConfig(cc.CurrentConfig())
+	}
+	return component.MarshalConfig(cn.Arguments())
+}
+
 // setEvalHealth sets the internal health from a call to Evaluate. See Health
 // for information on how overall health is calculated.
 func (cn *ComponentNode) setEvalHealth(t component.HealthType, msg string) {
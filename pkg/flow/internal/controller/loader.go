@@ -91,6 +91,10 @@ func NewLoader(globals ComponentGlobals) *Loader {
 // Reused components will be updated to point at the new River block.
 //
 // Apply will perform an evaluation of all loaded components before returning.
+// Each component is also given the set of components it depends on, so that
+// when it's run it first waits for any of them implementing
+// component.ReadyComponent to become ready.
+//
 // The provided parentContext can be used to provide global variables and
 // functions to components. A child context will be constructed from the parent
 // to expose values of other components.
@@ -186,6 +190,10 @@ func (l *Loader) Apply(args map[string]any, componentBlocks []*ast.BlockStmt, co
 		return nil
 	})
 
+	for _, c := range components {
+		c.setDependencies(componentDependencies(&newGraph, c))
+	}
+
 	l.components = components
 	l.graph = &newGraph
 	l.cache.SyncIDs(componentIDs)
@@ -370,6 +378,19 @@ func (l *Loader) wireGraphEdges(g *dag.Graph) diag.Diagnostics {
 	return diags
 }
 
+// componentDependencies returns the components which n depends on, directly
+// or indirectly.
+func componentDependencies(g *dag.Graph, n dag.Node) []*ComponentNode {
+	var deps []*ComponentNode
+	_ = dag.Walk(g, g.Dependencies(n), func(dep dag.Node) error {
+		if cn, ok := dep.(*ComponentNode); ok {
+			deps = append(deps, cn)
+		}
+		return nil
+	})
+	return deps
+}
+
 // Variables returns the Variables the Loader exposes for other Flow components
 // to reference.
 func (l *Loader) Variables() map[string]interface{} {
@@ -2,6 +2,7 @@ package testcomponents
 
 import (
 	"context"
+	"strings"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
@@ -31,6 +32,11 @@ type PassthroughExports struct {
 	Output string `river:"output,attr,optional"`
 }
 
+// PassthroughUppercaseFlag is a feature flag which makes
+// testcomponents.passthrough emit its input in upper case. It exists to test
+// feature flags.
+const PassthroughUppercaseFlag = "testcomponents.passthrough.uppercase"
+
 // Passthrough implements the testcomponents.passthrough component, where it
 // always emits its input as an output.
 type Passthrough struct {
@@ -62,8 +68,13 @@ func (t *Passthrough) Run(ctx context.Context) error {
 func (t *Passthrough) Update(args component.Arguments) error {
 	c := args.(PassthroughConfig)
 
-	level.Info(t.log).Log("msg", "passing through value", "value", c.Input)
-	t.opts.OnStateChange(PassthroughExports{Output: c.Input})
+	output := c.Input
+	if t.opts.FeatureFlags.Enabled(PassthroughUppercaseFlag) {
+		output = strings.ToUpper(output)
+	}
+
+	level.Info(t.log).Log("msg", "passing through value", "value", output)
+	t.opts.OnStateChange(PassthroughExports{Output: output})
 	return nil
 }
 
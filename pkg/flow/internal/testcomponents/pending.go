@@ -0,0 +1,84 @@
+package testcomponents
+
+import (
+	"context"
+	"sync"
+
+	"github.com/grafana/agent/component"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name: "testcomponents.pending",
+		Args: PendingConfig{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return NewPending(opts, args.(PendingConfig))
+		},
+	})
+}
+
+// PendingConfig configures the testcomponents.pending component.
+type PendingConfig struct {
+	// Items is the number of items queued when the component is built.
+	Items int `river:"items,attr"`
+	// Drain is the number of queued items processed when the component is
+	// stopped.
+	Drain int `river:"drain,attr,optional"`
+}
+
+// Pending implements the testcomponents.pending component, which holds a
+// fixed queue of items and processes some of them when it is stopped.
+type Pending struct {
+	opts component.Options
+
+	mut     sync.Mutex
+	pending int
+	drain   int
+}
+
+// NewPending creates a new testcomponents.pending component. The queued items
+// can't be changed after the component is built.
+func NewPending(o component.Options, cfg PendingConfig) (*Pending, error) {
+	t := &Pending{opts: o, pending: cfg.Items}
+	if err := t.Update(cfg); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+var (
+	_ component.Component      = (*Pending)(nil)
+	_ component.DrainComponent = (*Pending)(nil)
+)
+
+// Run implements Component.
+func (t *Pending) Run(ctx context.Context) error {
+	<-ctx.Done()
+
+	t.mut.Lock()
+	defer t.mut.Unlock()
+	drained := t.drain
+	if drained > t.pending {
+		drained = t.pending
+	}
+	t.pending -= drained
+	return nil
+}
+
+// PendingWork implements DrainComponent.
+func (t *Pending) PendingWork() int {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+	return t.pending
+}
+
+// Update implements Component.
+func (t *Pending) Update(args component.Arguments) error {
+	c := args.(PendingConfig)
+
+	t.mut.Lock()
+	defer t.mut.Unlock()
+	t.drain = c.Drain
+	return nil
+}
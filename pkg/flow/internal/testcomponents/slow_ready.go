@@ -0,0 +1,90 @@
+package testcomponents
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/agent/component"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:    "testcomponents.slow_ready",
+		Args:    SlowReadyConfig{},
+		Exports: SlowReadyExports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return NewSlowReady(opts, args.(SlowReadyConfig))
+		},
+	})
+}
+
+// SlowReadyConfig configures the testcomponents.slow_ready component.
+type SlowReadyConfig struct {
+	Delay time.Duration `river:"delay,attr"`
+	Input string        `river:"input,attr"`
+}
+
+// SlowReadyExports describes exported fields for the
+// testcomponents.slow_ready component.
+type SlowReadyExports struct {
+	Output string `river:"output,attr,optional"`
+}
+
+// SlowReady implements the testcomponents.slow_ready component, which emits
+// its input as an output and reports itself ready once it has been running
+// for the configured delay.
+type SlowReady struct {
+	opts  component.Options
+	delay time.Duration
+
+	readyOnce sync.Once
+	ready     chan struct{}
+}
+
+// NewSlowReady creates a new testcomponents.slow_ready component. The delay
+// can't be changed after the component is built.
+func NewSlowReady(o component.Options, cfg SlowReadyConfig) (*SlowReady, error) {
+	t := &SlowReady{opts: o, delay: cfg.Delay, ready: make(chan struct{})}
+	if err := t.Update(cfg); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+var (
+	_ component.Component      = (*SlowReady)(nil)
+	_ component.ReadyComponent = (*SlowReady)(nil)
+)
+
+// Run implements Component.
+func (t *SlowReady) Run(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-time.After(t.delay):
+		// The component stays ready if it's run again.
+		t.readyOnce.Do(func() { close(t.ready) })
+	}
+
+	<-ctx.Done()
+	return nil
+}
+
+// WaitReady implements ReadyComponent.
+func (t *SlowReady) WaitReady(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.ready:
+		return nil
+	}
+}
+
+// Update implements Component.
+func (t *SlowReady) Update(args component.Arguments) error {
+	c := args.(SlowReadyConfig)
+	t.opts.OnStateChange(SlowReadyExports{Output: c.Input})
+	return nil
+}
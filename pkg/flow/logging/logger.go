@@ -4,6 +4,7 @@ import (
 	"io"
 
 	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
 )
 
 // Logger is a logger for Grafana Agent Flow components and controllers. It
@@ -13,8 +14,10 @@ type Logger struct {
 
 	parentComponentID string
 	componentID       string
+	level             Level // Level override; empty to use the level of the sink.
 
 	orig log.Logger // Original logger before the component name was added.
+	raw  log.Logger // Original logger without any level filter.
 	log  log.Logger // Logger with component name injected.
 }
 
@@ -28,13 +31,18 @@ func New(sink *Sink, opts ...LoggerOption) *Logger {
 		sink:              sink,
 		parentComponentID: sink.parentComponentID,
 		orig:              sink.logger,
+		raw:               sink.raw,
 	}
 	for _, opt := range opts {
 		opt(l)
 	}
 
+	if l.level != "" {
+		l.orig = level.NewFilter(l.raw, l.level.Filter())
+	}
+
 	// Build the final logger.
-	l.log = wrapWithComponentID(sink.logger, sink.parentComponentID, l.componentID)
+	l.log = wrapWithComponentID(l.orig, sink.parentComponentID, l.componentID)
 
 	return l
 }
@@ -49,6 +57,15 @@ func WithComponentID(id string) LoggerOption {
 	}
 }
 
+// WithLevel overrides the level of logs emitted by the Logger, ignoring the
+// level configured for its Sink. Loggers created from a LoggerSink of this
+// Logger inherit the override.
+func WithLevel(lvl Level) LoggerOption {
+	return func(l *Logger) {
+		l.level = lvl
+	}
+}
+
 // Log implements log.Logger.
 func (c *Logger) Log(kvps ...interface{}) error {
 	return c.log.Log(kvps...)
@@ -1,10 +1,13 @@
 package logging_test
 
 import (
+	"bytes"
 	"os"
+	"testing"
 
 	"github.com/go-kit/log/level"
 	"github.com/grafana/agent/pkg/flow/logging"
+	"github.com/stretchr/testify/require"
 )
 
 func Example() {
@@ -42,3 +45,42 @@ func Example() {
 	// component=outer/inner level=info msg="hello from the inner component!"
 	// component=outer/inner level=info msg="hello from the inner controller!"
 }
+
+func TestWithLevel(t *testing.T) {
+	var buf bytes.Buffer
+	sink, err := logging.WriterSink(&buf, logging.SinkOptions{
+		Level:  logging.LevelInfo,
+		Format: logging.FormatLogfmt,
+	})
+	require.NoError(t, err)
+
+	controller := logging.New(sink)
+	verbose := logging.New(logging.LoggerSink(controller), logging.WithComponentID("verbose"), logging.WithLevel(logging.LevelDebug))
+	quiet := logging.New(logging.LoggerSink(controller), logging.WithComponentID("quiet"), logging.WithLevel(logging.LevelError))
+	inherited := logging.New(logging.LoggerSink(verbose), logging.WithComponentID("inner"))
+	normal := logging.New(logging.LoggerSink(controller), logging.WithComponentID("normal"))
+
+	for _, l := range []*logging.Logger{controller, verbose, quiet, inherited, normal} {
+		level.Debug(l).Log("msg", "debug line")
+		level.Info(l).Log("msg", "info line")
+	}
+
+	expect := `level=info msg="info line"
+component=verbose level=debug msg="debug line"
+component=verbose level=info msg="info line"
+component=verbose/inner level=debug msg="debug line"
+component=verbose/inner level=info msg="info line"
+component=normal level=info msg="info line"
+`
+	require.Equal(t, expect, buf.String())
+
+	// Updating the sink must not affect loggers with an override.
+	buf.Reset()
+	require.NoError(t, sink.Update(logging.SinkOptions{
+		Level:  logging.LevelError,
+		Format: logging.FormatLogfmt,
+	}))
+	level.Info(verbose).Log("msg", "still logged")
+	level.Info(normal).Log("msg", "filtered")
+	require.Equal(t, "component=verbose level=info msg=\"still logged\"\n", buf.String())
+}
@@ -19,6 +19,7 @@ type Sink struct {
 	parentComponentID string
 
 	logger *lazyLogger // Constructed logger to use.
+	raw    *lazyLogger // Constructed logger without the level filter applied.
 	opts   SinkOptions
 }
 
@@ -29,7 +30,7 @@ func WriterSink(w io.Writer, o SinkOptions) (*Sink, error) {
 		w = io.Discard
 	}
 
-	l, err := writerSinkLogger(w, o)
+	l, raw, err := writerSinkLogger(w, o)
 	if err != nil {
 		return nil, err
 	}
@@ -39,6 +40,7 @@ func WriterSink(w io.Writer, o SinkOptions) (*Sink, error) {
 		updatable: true,
 
 		logger: &lazyLogger{inner: l},
+		raw:    &lazyLogger{inner: raw},
 		opts:   o,
 	}, nil
 }
@@ -52,6 +54,7 @@ func LoggerSink(c *Logger) *Sink {
 
 		w:      io.Discard,
 		logger: &lazyLogger{inner: c.orig},
+		raw:    &lazyLogger{inner: c.raw},
 	}
 }
 
@@ -69,31 +72,30 @@ func (s *Sink) Update(o SinkOptions) error {
 	}
 
 	s.opts = o
-	l, err := writerSinkLogger(s.w, s.opts)
+	l, raw, err := writerSinkLogger(s.w, s.opts)
 	if err != nil {
 		return err
 	}
 
 	s.logger.UpdateInner(l)
+	s.raw.UpdateInner(raw)
 	return nil
 }
 
-func writerSinkLogger(w io.Writer, o SinkOptions) (log.Logger, error) {
-	var l log.Logger
-
+// writerSinkLogger returns a logger which writes to w filtered by the level
+// in o, along with the same logger without the level filter applied.
+func writerSinkLogger(w io.Writer, o SinkOptions) (filtered, raw log.Logger, err error) {
 	switch o.Format {
 	case FormatLogfmt:
-		l = log.NewLogfmtLogger(log.NewSyncWriter(w))
+		raw = log.NewLogfmtLogger(log.NewSyncWriter(w))
 	case FormatJSON:
-		l = log.NewJSONLogger(log.NewSyncWriter(w))
+		raw = log.NewJSONLogger(log.NewSyncWriter(w))
 	default:
-		return nil, fmt.Errorf("unrecognized log format %q", o.Format)
+		return nil, nil, fmt.Errorf("unrecognized log format %q", o.Format)
 	}
 
-	l = level.NewFilter(l, o.Level.Filter())
-
 	if o.IncludeTimestamps {
-		l = log.With(l, "ts", log.DefaultTimestampUTC)
+		raw = log.With(raw, "ts", log.DefaultTimestampUTC)
 	}
-	return l, nil
+	return level.NewFilter(raw, o.Level.Filter()), raw, nil
 }